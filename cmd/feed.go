@@ -0,0 +1,50 @@
+// Package cmd holds all top-level cobra commands. Each file should contain
+// only one command and that command should have only one purpose.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/feed"
+	"github.com/chelnak/gh-changelog/internal/get"
+	"github.com/spf13/cobra"
+)
+
+var feedFormat string
+
+// feedCmd prints an Atom or RSS feed of the entries in the changelog
+var feedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "Prints an Atom or RSS feed of the entries in the changelog",
+	Long: `Prints an Atom or RSS feed of the entries in the changelog.
+
+The feed is built from the changelog file, so it does not need access to GitHub.
+Each entry becomes an item that links to its tag, with the changes of the entry
+as HTML content. Publish the feed next to your documentation so that users can
+follow your releases with a feed reader.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		fileName, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
+
+		changelog, err := get.GetAll(fileName, repoContext.Owner, repoContext.Name)
+		if err != nil {
+			return err
+		}
+
+		return feed.Write(os.Stdout, feedFormat, changelog)
+	},
+}
+
+func init() {
+	feedCmd.Flags().StringVar(
+		&feedFormat,
+		"format",
+		feed.FormatAtom,
+		fmt.Sprintf("The format of the feed. Valid values are %s.", strings.Join(feed.Formats, ", ")),
+	)
+}
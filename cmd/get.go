@@ -5,6 +5,8 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/chelnak/gh-changelog/internal/configuration"
 	"github.com/chelnak/gh-changelog/internal/get"
@@ -18,6 +20,8 @@ type outputEnum string
 const (
 	outputStandard outputEnum = "standard"
 	outputNotes    outputEnum = "notes"
+	outputJSON     outputEnum = "json"
+	outputYAML     outputEnum = "yaml"
 )
 
 func (e *outputEnum) String() string {
@@ -26,11 +30,11 @@ func (e *outputEnum) String() string {
 
 func (e *outputEnum) Set(v string) error {
 	switch v {
-	case string(outputStandard), string(outputNotes):
+	case string(outputStandard), string(outputNotes), string(outputJSON), string(outputYAML):
 		*e = outputEnum(v)
 		return nil
 	default:
-		return fmt.Errorf(`must be one of %s or %s`, outputStandard, outputNotes)
+		return fmt.Errorf(`must be one of %s, %s, %s or %s`, outputStandard, outputNotes, outputJSON, outputYAML)
 	}
 }
 
@@ -41,6 +45,8 @@ func (e *outputEnum) Type() string {
 var outputTemplate = outputStandard
 var printLatest bool
 var printVersion string
+var getTemplateFile string
+var getFormat string
 
 // getCmd retrieves a local changelog and prints it to stdout
 var getCmd = &cobra.Command{
@@ -58,33 +64,63 @@ This command is useful for creating and updating Release notes in GitHub.
 │→ gh release create --title "Release v1.0.0" -F release_notes.md     │
 │                                                                     │
 └─────────────────────────────────────────────────────────────────────┘
+
+The release command can also create or update the release directly.
 `,
 	RunE: func(command *cobra.Command, args []string) error {
-		fileName := configuration.Config.FileName
+		if err := writer.ValidateFormat(getFormat); err != nil {
+			return err
+		}
+
+		fileName, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
 
 		var tmplSrc string
 		var changelog changelog.Changelog
-		var err error
 
 		if printLatest {
-			changelog, err = get.GetLatest(fileName)
+			changelog, err = get.GetLatest(fileName, repoContext.Owner, repoContext.Name)
 		} else if printVersion != "" {
-			changelog, err = get.GetVersion(fileName, printVersion)
+			changelog, err = get.GetVersion(fileName, printVersion, repoContext.Owner, repoContext.Name)
 		} else if outputTemplate == outputNotes {
 			err = fmt.Errorf("notes output only supported with latest or version")
 		} else {
-			changelog, err = get.GetAll(fileName)
+			changelog, err = get.GetAll(fileName, repoContext.Owner, repoContext.Name)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if getFormat != "" && getFormat != writer.FormatMarkdown {
+			var buf bytes.Buffer
+			if err := writer.Render(&buf, getFormat, "", changelog); err != nil {
+				return err
+			}
+
+			fmt.Print(buf.String())
+
+			return nil
 		}
 
 		switch outputTemplate {
 		case outputStandard:
-			tmplSrc = writer.TmplSrcStandard
+			if getTemplateFile == "" {
+				getTemplateFile = configuration.Config.TemplateFile
+			}
+
+			tmplSrc, err = writer.ResolveTemplate(getTemplateFile)
+			if err != nil {
+				return err
+			}
 		case outputNotes:
 			tmplSrc = writer.TmplSrcNotes
-		}
-
-		if err != nil {
-			return err
+		case outputJSON:
+			return writer.WriteJSON(os.Stdout, changelog)
+		case outputYAML:
+			return writer.WriteYAML(os.Stdout, changelog)
 		}
 
 		var buf bytes.Buffer
@@ -116,8 +152,25 @@ func init() {
 	getCmd.Flags().Var(
 		&outputTemplate,
 		"output",
-		fmt.Sprintf(`Output template. allowed: "%s" or "%s"`, outputStandard, outputNotes),
+		fmt.Sprintf(`Output format. allowed: "%s", "%s", "%s" or "%s"`, outputStandard, outputNotes, outputJSON, outputYAML),
+	)
+
+	getCmd.Flags().StringVar(
+		&getTemplateFile,
+		"template",
+		"",
+		"The path to a Go text/template file that is used to render the changelog.\nOverrides the template_file configuration.",
+	)
+
+	getCmd.Flags().StringVar(
+		&getFormat,
+		"format",
+		writer.FormatMarkdown,
+		fmt.Sprintf("The format of the changelog. Valid values are %s.", strings.Join(writer.Formats, ", ")),
 	)
 
+	getCmd.MarkFlagsMutuallyExclusive("output", "template")
+	getCmd.MarkFlagsMutuallyExclusive("output", "format")
+	getCmd.MarkFlagsMutuallyExclusive("format", "template")
 	getCmd.Flags().SortFlags = false
 }
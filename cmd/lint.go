@@ -0,0 +1,75 @@
+// Package cmd holds all top-level cobra commands. Each file should contain
+// only one command and that command should have only one purpose.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var lintFormat string
+var lintStrict bool
+
+// lintCmd checks a changelog file for problems
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Checks a changelog file for problems",
+	Long: `Checks a changelog file for problems.
+
+Versions must be in descending order and listed only once, each entry must have
+a valid date and a Full Changelog link that compares it with the previous version
+in the same repository.
+Unknown or empty sections and a missing Unreleased section are reported as warnings.
+
+The command exits with a non-zero exit code when an error is found, or when a
+warning is found and --strict is used.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		fileName, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
+
+		var tagPrefix string
+		if packageName != "" {
+			pkg, err := configuration.Config.GetPackage(packageName)
+			if err != nil {
+				return err
+			}
+
+			tagPrefix = pkg.TagPrefix
+		}
+
+		problems, err := lint.Lint(fileName, repoContext.Owner, repoContext.Name, tagPrefix)
+		if err != nil {
+			return err
+		}
+
+		switch lintFormat {
+		case "text":
+			err = lint.WriteText(os.Stdout, fileName, problems)
+		case "json":
+			err = lint.WriteJSON(os.Stdout, fileName, problems)
+		default:
+			err = fmt.Errorf("invalid format. Valid values are 'text' and 'json'")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if lint.HasErrors(problems, lintStrict) {
+			return errSilent
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "The output format. Valid values are 'text' and 'json'.")
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "Exit with a non-zero exit code when warnings are found.")
+}
@@ -3,8 +3,11 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/chelnak/gh-changelog/internal/configuration"
 	"github.com/chelnak/gh-changelog/internal/writer"
@@ -16,6 +19,11 @@ var nextVersion string
 var fromVersion string
 var latestVersion bool
 var logger string
+var source string
+var templateFile string
+var format string
+var line string
+var branch string
 
 // newCmd is the entry point for creating a new changelog
 var newCmd = &cobra.Command{
@@ -23,38 +31,72 @@ var newCmd = &cobra.Command{
 	Short: "Creates a new changelog from activity in the current repository",
 	Long:  "Creates a new changelog from activity in the current repository.",
 	RunE: func(command *cobra.Command, args []string) error {
+		if err := writer.ValidateFormat(format); err != nil {
+			return err
+		}
+
+		fileName, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
+
+		// Other formats are written next to the markdown changelog, which is
+		// the file that the other commands read.
+		fileName = writer.FileName(fileName, format)
+
 		opts := builder.BuilderOptions{
 			Logger:        logger,
+			Source:        source,
 			NextVersion:   nextVersion,
 			FromVersion:   fromVersion,
 			LatestVersion: latestVersion,
+			Repo:          repo,
+			Package:       packageName,
+			Line:          line,
+			Branch:        branch,
+			FileName:      fileName,
 		}
 
-		builder, err := builder.NewBuilder(opts)
-		if err != nil {
-			return err
+		var tmplSrc string
+		if format == "" || format == writer.FormatMarkdown {
+			if templateFile == "" {
+				templateFile = configuration.Config.TemplateFile
+			}
+
+			tmplSrc, err = writer.ResolveTemplate(templateFile)
+			if err != nil {
+				return err
+			}
 		}
 
-		changelog, err := builder.BuildChangelog()
+		builder, err := builder.NewBuilder(opts)
 		if err != nil {
 			return err
 		}
 
-		f, err := os.Create(filepath.Clean(configuration.Config.FileName))
+		changelog, err := builder.BuildChangelog()
 		if err != nil {
 			return err
 		}
 
-		if err := writer.Write(f, writer.TmplSrcStandard, changelog); err != nil {
+		// The changelog is rendered before the file is replaced, so that a
+		// template that fails to execute does not leave it empty.
+		var buf bytes.Buffer
+		if err := writer.Render(&buf, format, tmplSrc, changelog); err != nil {
 			return err
 		}
 
-		return nil
+		return os.WriteFile(filepath.Clean(fileName), buf.Bytes(), 0600)
 	},
 }
 
 func init() {
-	newCmd.Flags().StringVar(&nextVersion, "next-version", "", "The next version to be released. The value passed does not have to be an existing tag.")
+	newCmd.Flags().StringVar(
+		&nextVersion,
+		"next-version",
+		"",
+		"The next version to be released. The value passed does not have to be an existing tag.\nUse 'auto' to calculate the next version from the unreleased entries.",
+	)
 
 	newCmd.Flags().StringVar(
 		&fromVersion,
@@ -70,8 +112,34 @@ func init() {
 		"Build the changelog starting from the latest tag. Using this flag will result in a changelog with one entry.\nIt can be useful for generating a changelog to be used in release notes.",
 	)
 
+	newCmd.Flags().StringVar(&line, "line", "", "Only use tags in a version line, such as 1.x or 1.4.x.\nPull requests are assigned to releases with the commit graph.")
+
+	newCmd.Flags().StringVar(&branch, "branch", "", "Only use tags that are reachable from a branch, such as release/1.4.\nPull requests are assigned to releases with the commit graph.")
+
 	newCmd.Flags().StringVar(&logger, "logger", "", "The type of logger to use. Valid values are 'spinner' and 'console'. The default is 'spinner'.")
 
+	newCmd.Flags().StringVar(
+		&source,
+		"source",
+		builder.SourceGitHub,
+		"The source of tags and pull requests. Valid values are 'github' and 'git'.\nThe 'git' source works offline by reading merge commits from the local repository.",
+	)
+
+	newCmd.Flags().StringVar(
+		&templateFile,
+		"template",
+		"",
+		"The path to a Go text/template file that is used to render the changelog.\nOverrides the template_file configuration.",
+	)
+
+	newCmd.Flags().StringVar(
+		&format,
+		"format",
+		writer.FormatMarkdown,
+		fmt.Sprintf("The format of the changelog. Valid values are %s.\nOther formats are written to file_name with the extension of the format, such as CHANGELOG.html.\nOnly the markdown format can be rendered with a custom template.", strings.Join(writer.Formats, ", ")),
+	)
+
 	newCmd.MarkFlagsMutuallyExclusive("from-version", "latest")
+	newCmd.MarkFlagsMutuallyExclusive("format", "template")
 	newCmd.Flags().SortFlags = false
 }
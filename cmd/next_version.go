@@ -0,0 +1,68 @@
+// Package cmd holds all top-level cobra commands. Each file should contain
+// only one command and that command should have only one purpose.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/chelnak/gh-changelog/pkg/builder"
+	"github.com/spf13/cobra"
+)
+
+var nextVersionSource string
+var nextVersionLogger string
+var nextVersionLine string
+var nextVersionBranch string
+
+// nextVersionCmd calculates the next version from the unreleased entries
+var nextVersionCmd = &cobra.Command{
+	Use:   "next-version",
+	Short: "Calculates the next version from unreleased changes and prints it to stdout",
+	Long: `Calculates the next version from unreleased changes and prints it to stdout.
+
+The latest tag is incremented based on the sections of the unreleased entries.
+By default, entries in Changed or Removed increment the major version, entries
+in Added or Deprecated increment the minor version and anything else increments
+the patch version. This can be changed with the version_increments configuration.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		opts := builder.BuilderOptions{
+			Logger:  nextVersionLogger,
+			Source:  nextVersionSource,
+			Repo:    repo,
+			Package: packageName,
+			Line:    nextVersionLine,
+			Branch:  nextVersionBranch,
+		}
+
+		builder, err := builder.NewBuilder(opts)
+		if err != nil {
+			return err
+		}
+
+		nextVersion, err := builder.NextVersion()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(nextVersion)
+
+		return nil
+	},
+}
+
+func init() {
+	nextVersionCmd.Flags().StringVar(
+		&nextVersionSource,
+		"source",
+		builder.SourceGitHub,
+		"The source of tags and pull requests. Valid values are 'github' and 'git'.",
+	)
+
+	nextVersionCmd.Flags().StringVar(&nextVersionLine, "line", "", "Only use tags in a version line, such as 1.x or 1.4.x.\nPull requests are assigned to releases with the commit graph.")
+
+	nextVersionCmd.Flags().StringVar(&nextVersionBranch, "branch", "", "Only use tags that are reachable from a branch, such as release/1.4.\nPull requests are assigned to releases with the commit graph.")
+
+	nextVersionCmd.Flags().StringVar(&nextVersionLogger, "logger", "console", "The type of logger to use. Valid values are 'spinner' and 'console'. The default is 'console'.")
+
+	nextVersionCmd.Flags().SortFlags = false
+}
@@ -18,9 +18,12 @@ var parseCmd = &cobra.Command{
 	Long:   "EXPERIMENTAL: Parse a changelog file in to a Changelog struct",
 	Hidden: true,
 	RunE: func(command *cobra.Command, args []string) error {
-		changelog := configuration.Config.FileName
+		changelog, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
 
-		parser := parser.NewParser(changelog, "", "")
+		parser := parser.NewParser(changelog, repoContext.Owner, repoContext.Name)
 		cl, err := parser.Parse()
 		if err != nil {
 			return err
@@ -0,0 +1,117 @@
+// Package cmd holds all top-level cobra commands. Each file should contain
+// only one command and that command should have only one purpose.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/get"
+	"github.com/chelnak/gh-changelog/internal/release"
+	"github.com/chelnak/gh-changelog/internal/utils"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var releaseVersion string
+var releaseDraft bool
+var releasePrerelease bool
+var releaseSyncAll bool
+
+// releaseCmd publishes GitHub releases from the changelog
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Creates or updates a GitHub release from an entry in the changelog",
+	Long: `Creates or updates a GitHub release from an entry in the changelog.
+
+The release notes are rendered in the same way as 'gh changelog get --output notes'.
+The latest entry is released unless --version is used. If the release already
+exists, its notes are replaced. The --draft and --prerelease flags are only
+applied to an existing release when they are set, otherwise it keeps its own.
+
+Use --sync-all to update the notes of every existing release so that they match
+the changelog. Releases are not created for entries that do not have one.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		fileName, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
+
+		ctx := repoContext
+		if ctx.Owner == "" {
+			ctx, err = utils.GetRepoContext()
+			if err != nil {
+				return err
+			}
+		}
+
+		client, err := release.NewClient(ctx, "")
+		if err != nil {
+			return err
+		}
+
+		var results []release.Result
+		if releaseSyncAll {
+			changelogs, err := get.GetEach(fileName, ctx.Owner, ctx.Name)
+			if err != nil {
+				return err
+			}
+
+			results, err = release.Sync(client, changelogs)
+			if err != nil {
+				return err
+			}
+		} else {
+			var cl changelog.Changelog
+			if releaseVersion != "" {
+				cl, err = get.GetVersion(fileName, releaseVersion, ctx.Owner, ctx.Name)
+			} else {
+				cl, err = get.GetLatest(fileName, ctx.Owner, ctx.Name)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			var opts release.Options
+			if command.Flags().Changed("draft") {
+				opts.Draft = &releaseDraft
+			}
+			if command.Flags().Changed("prerelease") {
+				opts.Prerelease = &releasePrerelease
+			}
+
+			result, err := release.Publish(client, cl, opts)
+			if err != nil {
+				return err
+			}
+
+			results = append(results, result)
+		}
+
+		for _, result := range results {
+			switch result.Action {
+			case release.ActionSkipped:
+				fmt.Printf("%s: skipped, there is no release for this tag\n", result.Tag)
+			case release.ActionUnchanged:
+				fmt.Printf("%s: unchanged\n", result.Tag)
+			default:
+				fmt.Printf("%s: %s %s\n", result.Tag, result.Action, result.URL)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&releaseVersion, "version", "", "The version to release. The default is the latest version in the changelog.")
+	releaseCmd.Flags().BoolVar(&releaseDraft, "draft", false, "Save the release as a draft instead of publishing it.")
+	releaseCmd.Flags().BoolVar(&releasePrerelease, "prerelease", false, "Mark the release as a pre-release.")
+	releaseCmd.Flags().BoolVar(&releaseSyncAll, "sync-all", false, "Update the notes of every existing release so that they match the changelog.")
+
+	releaseCmd.MarkFlagsMutuallyExclusive("sync-all", "version")
+	releaseCmd.MarkFlagsMutuallyExclusive("sync-all", "draft")
+	releaseCmd.MarkFlagsMutuallyExclusive("sync-all", "prerelease")
+	releaseCmd.Flags().SortFlags = false
+}
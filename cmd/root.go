@@ -14,6 +14,13 @@ import (
 
 var version = "dev"
 var errSilent = errors.New("ErrSilent")
+var hostname string
+var repo string
+var packageName string
+
+// repoContext holds the repository passed with --repo. It is empty when the
+// repository in the current directory is used.
+var repoContext utils.RepoContext
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -53,6 +60,32 @@ Issues or feature requests can be opened at:
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	Run:           nil,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The GitHub CLI libraries read the host from GH_HOST, so setting it
+		// here applies the flag to API requests and repository detection.
+		if hostname != "" {
+			if err := os.Setenv("GH_HOST", hostname); err != nil {
+				return err
+			}
+		}
+
+		if repo == "" {
+			return nil
+		}
+
+		var err error
+		repoContext, err = utils.ParseRepoContext(repo)
+		if err != nil {
+			return err
+		}
+
+		// A host in --repo applies to everything, in the same way as --hostname.
+		if hostname == "" {
+			return os.Setenv("GH_HOST", repoContext.Host)
+		}
+
+		return nil
+	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		if configuration.Config.CheckForUpdates {
 			utils.CheckForUpdate(version)
@@ -73,11 +106,38 @@ func init() {
 		return errSilent
 	})
 
+	rootCmd.PersistentFlags().StringVar(
+		&hostname,
+		"hostname",
+		"",
+		"The hostname of the GitHub instance, for example a GitHub Enterprise Server instance.\nDefaults to the host of the repository remote.",
+	)
+
+	rootCmd.PersistentFlags().StringVarP(
+		&repo,
+		"repo",
+		"R",
+		"",
+		"Select another repository using the [HOST/]OWNER/NAME format.\nTags and pull requests are read from the GitHub API instead of the local repository.",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&packageName,
+		"package",
+		"",
+		"The name of a package in the packages configuration.\nOnly tags with the tag prefix of the package and pull requests that changed its paths are used.",
+	)
+
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(getCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(parseCmd)
+	rootCmd.AddCommand(nextVersionCmd)
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(feedCmd)
 }
 
 func formatError(err error) {
@@ -14,7 +14,11 @@ var showCmd = &cobra.Command{
 	Short: "Renders the current changelog in the terminal",
 	Long:  "Renders the current changelog in the terminal",
 	RunE: func(command *cobra.Command, args []string) error {
-		changelog := configuration.Config.FileName
+		changelog, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
+
 		return show.Render(changelog)
 	},
 }
@@ -0,0 +1,108 @@
+// Package cmd holds all top-level cobra commands. Each file should contain
+// only one command and that command should have only one purpose.
+package cmd
+
+import (
+	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/update"
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/builder"
+	"github.com/spf13/cobra"
+)
+
+var updateNextVersion string
+var updateLogger string
+var updateSource string
+var updateTemplateFile string
+var updateLine string
+var updateBranch string
+
+// updateCmd adds new releases to an existing changelog
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Adds new releases to an existing changelog",
+	Long: `Adds new releases to an existing changelog.
+
+Only tags that are newer than the latest entry in the changelog are processed.
+The unreleased section is regenerated and entries that are already in the
+changelog are kept exactly as they are, including any changes made by hand.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		fileName, err := configuration.Config.GetFileName(packageName)
+		if err != nil {
+			return err
+		}
+
+		existing, err := update.Read(fileName, repoContext.Owner, repoContext.Name)
+		if err != nil {
+			return err
+		}
+
+		latestVersion := update.GetLatestVersion(existing)
+
+		if updateTemplateFile == "" {
+			updateTemplateFile = configuration.Config.TemplateFile
+		}
+
+		tmplSrc, err := writer.ResolveTemplate(updateTemplateFile)
+		if err != nil {
+			return err
+		}
+
+		opts := builder.BuilderOptions{
+			Logger:       updateLogger,
+			Source:       updateSource,
+			NextVersion:  updateNextVersion,
+			SinceVersion: latestVersion,
+			Repo:         repo,
+			Package:      packageName,
+			Line:         updateLine,
+			Branch:       updateBranch,
+		}
+
+		builder, err := builder.NewBuilder(opts)
+		if err != nil {
+			return err
+		}
+
+		changelog, err := builder.BuildChangelog()
+		if err != nil {
+			return err
+		}
+
+		changelog.SetPreamble(existing.GetPreamble())
+		changelog.SetUnreleasedDescription(existing.GetUnreleasedDescription())
+
+		return update.WriteFile(fileName, tmplSrc, changelog, latestVersion)
+	},
+}
+
+func init() {
+	updateCmd.Flags().StringVar(
+		&updateNextVersion,
+		"next-version",
+		"",
+		"The next version to be released. The value passed does not have to be an existing tag.\nUse 'auto' to calculate the next version from the unreleased entries.",
+	)
+
+	updateCmd.Flags().StringVar(&updateLine, "line", "", "Only use tags in a version line, such as 1.x or 1.4.x.\nPull requests are assigned to releases with the commit graph.")
+
+	updateCmd.Flags().StringVar(&updateBranch, "branch", "", "Only use tags that are reachable from a branch, such as release/1.4.\nPull requests are assigned to releases with the commit graph.")
+
+	updateCmd.Flags().StringVar(&updateLogger, "logger", "", "The type of logger to use. Valid values are 'spinner' and 'console'. The default is 'spinner'.")
+
+	updateCmd.Flags().StringVar(
+		&updateSource,
+		"source",
+		builder.SourceGitHub,
+		"The source of tags and pull requests. Valid values are 'github' and 'git'.",
+	)
+
+	updateCmd.Flags().StringVar(
+		&updateTemplateFile,
+		"template",
+		"",
+		"The path to a Go text/template file that is used to render the changelog.\nOverrides the template_file configuration.",
+	)
+
+	updateCmd.Flags().SortFlags = false
+}
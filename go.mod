@@ -12,13 +12,13 @@ require (
 	github.com/chelnak/ysmrr v0.4.0
 	github.com/cli/go-gh/v2 v2.9.0
 	github.com/fatih/color v1.16.0
-	github.com/gomarkdown/markdown v0.0.0-20240419095408-642f0ee99ae2
 	github.com/jarcoal/httpmock v1.2.0
 	github.com/rs/zerolog v1.32.0
 	github.com/shurcooL/githubv4 v0.0.0-20240429030203-be2daab69064
 	github.com/spf13/cobra v1.8.0
 	github.com/spf13/viper v1.18.2
 	github.com/stretchr/testify v1.9.0
+	github.com/yuin/goldmark v1.7.1
 	golang.org/x/text v0.15.0
 	gopkg.in/h2non/gock.v1 v1.1.2
 	gopkg.in/yaml.v2 v2.4.0
@@ -66,7 +66,6 @@ require (
 	github.com/stretchr/objx v0.5.2 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
 	github.com/thlib/go-timezone-local v0.0.0-20210907160436-ef149e42d28e // indirect
-	github.com/yuin/goldmark v1.7.1 // indirect
 	github.com/yuin/goldmark-emoji v1.0.2 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842 // indirect
@@ -25,12 +25,61 @@ var Config configuration
 
 type configuration struct {
 	FileName                string              `mapstructure:"file_name" yaml:"file_name" json:"fileName"`
+	TemplateFile            string              `mapstructure:"template_file" yaml:"template_file" json:"templateFile"`
 	ExcludedLabels          []string            `mapstructure:"excluded_labels" yaml:"excluded_labels" json:"excludedLabels"`
+	ExcludedAuthors         []string            `mapstructure:"excluded_authors" yaml:"excluded_authors" json:"excludedAuthors"`
+	ExcludedTitlePatterns   []string            `mapstructure:"excluded_title_patterns" yaml:"excluded_title_patterns" json:"excludedTitlePatterns"`
+	IncludedAuthors         []string            `mapstructure:"included_authors" yaml:"included_authors" json:"includedAuthors"`
 	Sections                map[string][]string `mapstructure:"sections" yaml:"sections" json:"sections"`
+	SectionOrder            []string            `mapstructure:"section_order" yaml:"section_order" json:"sectionOrder"`
 	SkipEntriesWithoutLabel bool                `mapstructure:"skip_entries_without_label" yaml:"skip_entries_without_label" json:"skipEntriesWithoutLabel"`
 	ShowUnreleased          bool                `mapstructure:"show_unreleased" yaml:"show_unreleased" json:"showUnreleased"`
+	UseCommitGraph          bool                `mapstructure:"use_commit_graph" yaml:"use_commit_graph" json:"useCommitGraph"`
 	CheckForUpdates         bool                `mapstructure:"check_for_updates" yaml:"check_for_updates" json:"checkForUpdates"`
 	Logger                  string              `mapstructure:"logger" yaml:"logger" json:"logger"`
+	ConventionalCommits     conventionalCommits `mapstructure:"conventional_commits" yaml:"conventional_commits" json:"conventionalCommits"`
+	VersionIncrements       map[string]string   `mapstructure:"version_increments" yaml:"version_increments" json:"versionIncrements"`
+	Packages                map[string]Package  `mapstructure:"packages" yaml:"packages" json:"packages"`
+	Tags                    tags                `mapstructure:"tags" yaml:"tags" json:"tags"`
+	Prereleases             prereleases         `mapstructure:"prereleases" yaml:"prereleases" json:"prereleases"`
+	Contributors            contributors        `mapstructure:"contributors" yaml:"contributors" json:"contributors"`
+	DirectCommits           directCommits       `mapstructure:"direct_commits" yaml:"direct_commits" json:"directCommits"`
+}
+
+// Package describes a package in a monorepo that has its own changelog.
+type Package struct {
+	TagPrefix string   `mapstructure:"tag_prefix" yaml:"tag_prefix" json:"tagPrefix"`
+	Paths     []string `mapstructure:"paths" yaml:"paths" json:"paths"`
+	FileName  string   `mapstructure:"file_name" yaml:"file_name" json:"fileName"`
+}
+
+type conventionalCommits struct {
+	Enabled    bool              `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Precedence string            `mapstructure:"precedence" yaml:"precedence" json:"precedence"`
+	Types      map[string]string `mapstructure:"types" yaml:"types" json:"types"`
+}
+
+type tags struct {
+	Include    []string `mapstructure:"include" yaml:"include" json:"include"`
+	Exclude    []string `mapstructure:"exclude" yaml:"exclude" json:"exclude"`
+	SemverOnly bool     `mapstructure:"semver_only" yaml:"semver_only" json:"semverOnly"`
+	Sort       string   `mapstructure:"sort" yaml:"sort" json:"sort"`
+}
+
+type prereleases struct {
+	Fold        bool `mapstructure:"fold" yaml:"fold" json:"fold"`
+	KeepEntries bool `mapstructure:"keep_entries" yaml:"keep_entries" json:"keepEntries"`
+}
+
+type contributors struct {
+	Enabled     bool     `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Exclude     []string `mapstructure:"exclude" yaml:"exclude" json:"exclude"`
+	ExcludeBots bool     `mapstructure:"exclude_bots" yaml:"exclude_bots" json:"excludeBots"`
+}
+
+type directCommits struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Section string `mapstructure:"section" yaml:"section" json:"section"`
 }
 
 type writeOptions struct {
@@ -67,6 +116,35 @@ func prettyWrite(opts writeOptions) error {
 	return formatter.Format(opts.writer, style, iterator)
 }
 
+// GetPackage returns the configuration of the package with the given name.
+func (c *configuration) GetPackage(name string) (Package, error) {
+	pkg, ok := c.Packages[name]
+	if !ok {
+		return Package{}, fmt.Errorf("the package '%s' is not configured", name)
+	}
+
+	return pkg, nil
+}
+
+// GetFileName returns the file name of the changelog for the given package.
+// If name is empty, the file_name configuration is returned.
+func (c *configuration) GetFileName(name string) (string, error) {
+	if name == "" {
+		return c.FileName, nil
+	}
+
+	pkg, err := c.GetPackage(name)
+	if err != nil {
+		return "", err
+	}
+
+	if pkg.FileName == "" {
+		return "", fmt.Errorf("the package '%s' does not have a file_name", name)
+	}
+
+	return pkg.FileName, nil
+}
+
 func (c *configuration) PrintJSON(noColor bool, writer io.Writer) error {
 	b, err := json.MarshalIndent(c, "", "  ")
 	b = append(b, '\n')
@@ -144,7 +222,11 @@ func InitConfig() error {
 
 func setDefaults() {
 	viper.SetDefault("file_name", "CHANGELOG.md")
+	viper.SetDefault("template_file", "")
 	viper.SetDefault("excluded_labels", []string{"maintenance", "dependencies"})
+	viper.SetDefault("excluded_authors", []string{})
+	viper.SetDefault("excluded_title_patterns", []string{})
+	viper.SetDefault("included_authors", []string{})
 
 	sections := make(map[string][]string)
 	sections["changed"] = []string{"backwards-incompatible"}
@@ -153,13 +235,55 @@ func setDefaults() {
 
 	viper.SetDefault("sections", sections)
 
+	viper.SetDefault("section_order", []string{"security", "changed", "removed", "deprecated", "added", "fixed", "other"})
+
 	viper.SetDefault("skip_entries_without_label", false)
 
 	viper.SetDefault("show_unreleased", true)
 
+	viper.SetDefault("use_commit_graph", false)
+
 	viper.SetDefault("check_for_updates", true)
 
 	viper.SetDefault("no_color", false)
 
 	viper.SetDefault("logger", "spinner")
+
+	viper.SetDefault("conventional_commits.enabled", false)
+	viper.SetDefault("conventional_commits.precedence", "labels")
+
+	types := make(map[string]string)
+	types["feat"] = "added"
+	types["fix"] = "fixed"
+	types["perf"] = "other"
+	types["refactor"] = "other"
+	types["docs"] = "fixed"
+	types["security"] = "security"
+
+	viper.SetDefault("conventional_commits.types", types)
+
+	increments := make(map[string]string)
+	increments["changed"] = "major"
+	increments["removed"] = "major"
+	increments["added"] = "minor"
+	increments["deprecated"] = "minor"
+
+	viper.SetDefault("version_increments", increments)
+
+	viper.SetDefault("packages", map[string]Package{})
+
+	viper.SetDefault("tags.include", []string{})
+	viper.SetDefault("tags.exclude", []string{})
+	viper.SetDefault("tags.semver_only", false)
+	viper.SetDefault("tags.sort", "date")
+
+	viper.SetDefault("prereleases.fold", false)
+	viper.SetDefault("prereleases.keep_entries", false)
+
+	viper.SetDefault("contributors.enabled", false)
+	viper.SetDefault("contributors.exclude", []string{})
+	viper.SetDefault("contributors.exclude_bots", true)
+
+	viper.SetDefault("direct_commits.enabled", false)
+	viper.SetDefault("direct_commits.section", "other")
 }
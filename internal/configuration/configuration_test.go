@@ -15,19 +15,68 @@ func TestInitConfigSetsCorrectValues(t *testing.T) {
 	config := configuration.Config
 
 	assert.Equal(t, "CHANGELOG.md", config.FileName)
+	assert.Equal(t, "", config.TemplateFile)
 
 	assert.Equal(t, []string{"maintenance", "dependencies"}, config.ExcludedLabels)
 	assert.Equal(t, 2, len(config.ExcludedLabels))
+	assert.Equal(t, []string{}, config.ExcludedAuthors)
+	assert.Equal(t, []string{}, config.ExcludedTitlePatterns)
+	assert.Equal(t, []string{}, config.IncludedAuthors)
 
 	assert.True(t, containsKey(config.Sections, "changed"))
 	assert.True(t, containsKey(config.Sections, "added"))
 	assert.True(t, containsKey(config.Sections, "fixed"))
 
 	assert.Equal(t, 3, len(config.Sections))
+	assert.Equal(t, []string{"security", "changed", "removed", "deprecated", "added", "fixed", "other"}, config.SectionOrder)
 	assert.Equal(t, false, config.SkipEntriesWithoutLabel)
 	assert.Equal(t, true, config.ShowUnreleased)
+	assert.Equal(t, false, config.UseCommitGraph)
 	assert.Equal(t, true, config.CheckForUpdates)
 	assert.Equal(t, "spinner", config.Logger)
+
+	assert.Equal(t, false, config.ConventionalCommits.Enabled)
+	assert.Equal(t, "labels", config.ConventionalCommits.Precedence)
+	assert.Equal(t, "added", config.ConventionalCommits.Types["feat"])
+	assert.Equal(t, "fixed", config.ConventionalCommits.Types["fix"])
+
+	assert.Equal(t, "major", config.VersionIncrements["changed"])
+	assert.Equal(t, "minor", config.VersionIncrements["added"])
+
+	assert.Empty(t, config.Packages)
+
+	assert.Empty(t, config.Tags.Include)
+	assert.Empty(t, config.Tags.Exclude)
+	assert.Equal(t, false, config.Tags.SemverOnly)
+	assert.Equal(t, "date", config.Tags.Sort)
+
+	assert.Equal(t, false, config.Prereleases.Fold)
+	assert.Equal(t, false, config.Prereleases.KeepEntries)
+}
+
+func TestGetFileName(t *testing.T) {
+	err := configuration.InitConfig()
+	assert.NoError(t, err)
+
+	configuration.Config.Packages = map[string]configuration.Package{
+		"api": {TagPrefix: "api/", Paths: []string{"services/api/**"}, FileName: "services/api/CHANGELOG.md"},
+		"web": {TagPrefix: "web/"},
+	}
+	defer func() { configuration.Config.Packages = map[string]configuration.Package{} }()
+
+	fileName, err := configuration.Config.GetFileName("")
+	assert.NoError(t, err)
+	assert.Equal(t, "CHANGELOG.md", fileName)
+
+	fileName, err = configuration.Config.GetFileName("api")
+	assert.NoError(t, err)
+	assert.Equal(t, "services/api/CHANGELOG.md", fileName)
+
+	_, err = configuration.Config.GetFileName("web")
+	assert.EqualError(t, err, "the package 'web' does not have a file_name")
+
+	_, err = configuration.Config.GetFileName("cli")
+	assert.EqualError(t, err, "the package 'cli' is not configured")
 }
 
 func TestPrintJSON(t *testing.T) {
@@ -42,10 +91,14 @@ func TestPrintJSON(t *testing.T) {
 
 	cfg := `{
   "fileName": "CHANGELOG.md",
+  "templateFile": "",
   "excludedLabels": [
     "maintenance",
     "dependencies"
   ],
+  "excludedAuthors": [],
+  "excludedTitlePatterns": [],
+  "includedAuthors": [],
   "sections": {
     "added": [
       "feature",
@@ -60,10 +113,58 @@ func TestPrintJSON(t *testing.T) {
       "documentation"
     ]
   },
+  "sectionOrder": [
+    "security",
+    "changed",
+    "removed",
+    "deprecated",
+    "added",
+    "fixed",
+    "other"
+  ],
   "skipEntriesWithoutLabel": false,
   "showUnreleased": true,
+  "useCommitGraph": false,
   "checkForUpdates": true,
-  "logger": "spinner"
+  "logger": "spinner",
+  "conventionalCommits": {
+    "enabled": false,
+    "precedence": "labels",
+    "types": {
+      "docs": "fixed",
+      "feat": "added",
+      "fix": "fixed",
+      "perf": "other",
+      "refactor": "other",
+      "security": "security"
+    }
+  },
+  "versionIncrements": {
+    "added": "minor",
+    "changed": "major",
+    "deprecated": "minor",
+    "removed": "major"
+  },
+  "packages": {},
+  "tags": {
+    "include": [],
+    "exclude": [],
+    "semverOnly": false,
+    "sort": "date"
+  },
+  "prereleases": {
+    "fold": false,
+    "keepEntries": false
+  },
+  "contributors": {
+    "enabled": false,
+    "exclude": [],
+    "excludeBots": true
+  },
+  "directCommits": {
+    "enabled": false,
+    "section": "other"
+  }
 }
 `
 
@@ -82,9 +183,13 @@ func TestPrintYAML(t *testing.T) {
 
 	cfg := `---
 file_name: CHANGELOG.md
+template_file: ""
 excluded_labels:
 - maintenance
 - dependencies
+excluded_authors: []
+excluded_title_patterns: []
+included_authors: []
 sections:
   added:
   - feature
@@ -95,10 +200,50 @@ sections:
   - bug
   - bugfix
   - documentation
+section_order:
+- security
+- changed
+- removed
+- deprecated
+- added
+- fixed
+- other
 skip_entries_without_label: false
 show_unreleased: true
+use_commit_graph: false
 check_for_updates: true
 logger: spinner
+conventional_commits:
+  enabled: false
+  precedence: labels
+  types:
+    docs: fixed
+    feat: added
+    fix: fixed
+    perf: other
+    refactor: other
+    security: security
+version_increments:
+  added: minor
+  changed: major
+  deprecated: minor
+  removed: major
+packages: {}
+tags:
+  include: []
+  exclude: []
+  semver_only: false
+  sort: date
+prereleases:
+  fold: false
+  keep_entries: false
+contributors:
+  enabled: false
+  exclude: []
+  exclude_bots: true
+direct_commits:
+  enabled: false
+  section: other
 `
 	assert.Equal(t, cfg, buf.String())
 }
@@ -0,0 +1,49 @@
+// Package conventional provides helpers for working with titles that follow
+// the Conventional Commits specification.
+//
+// See https://www.conventionalcommits.org for more information.
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// type(scope)!: description
+var titleRegex = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?:\s+(.+)$`)
+
+// BREAKING CHANGE: description
+var breakingChangeRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// Title represents a title that has been parsed from a conventional commit
+// message or pull request title.
+type Title struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// Parse parses the given title. The second return value is false if the
+// title does not follow the Conventional Commits format.
+func Parse(title string) (Title, bool) {
+	m := titleRegex.FindStringSubmatch(strings.TrimSpace(title))
+	if m == nil {
+		return Title{}, false
+	}
+
+	return Title{
+		Type:        strings.ToLower(m[1]),
+		Scope:       m[2],
+		Breaking:    m[3] == "!",
+		Description: m[4],
+	}, true
+}
+
+// HasBreakingChange returns true if the given text contains a
+// BREAKING CHANGE footer. Only a line that starts with the footer token
+// counts, so a checkbox or heading that mentions breaking changes in a pull
+// request template does not.
+func HasBreakingChange(text string) bool {
+	return breakingChangeRegex.MatchString(text)
+}
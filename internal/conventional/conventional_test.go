@@ -0,0 +1,61 @@
+package conventional_test
+
+import (
+	"testing"
+
+	"github.com/chelnak/gh-changelog/internal/conventional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		title    string
+		ok       bool
+		expected conventional.Title
+	}{
+		{
+			title:    "feat: add a new feature",
+			ok:       true,
+			expected: conventional.Title{Type: "feat", Description: "add a new feature"},
+		},
+		{
+			title:    "fix(parser): handle empty files",
+			ok:       true,
+			expected: conventional.Title{Type: "fix", Scope: "parser", Description: "handle empty files"},
+		},
+		{
+			title:    "feat!: drop support for go 1.19",
+			ok:       true,
+			expected: conventional.Title{Type: "feat", Breaking: true, Description: "drop support for go 1.19"},
+		},
+		{
+			title:    "Perf(builder)!: rewrite tag discovery",
+			ok:       true,
+			expected: conventional.Title{Type: "perf", Scope: "builder", Breaking: true, Description: "rewrite tag discovery"},
+		},
+		{
+			title: "Add a new feature",
+			ok:    false,
+		},
+		{
+			title: "feat:missing space",
+			ok:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			title, ok := conventional.Parse(test.title)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, title)
+		})
+	}
+}
+
+func TestHasBreakingChange(t *testing.T) {
+	assert.True(t, conventional.HasBreakingChange("Some description\n\nBREAKING CHANGE: the api has changed"))
+	assert.True(t, conventional.HasBreakingChange("BREAKING-CHANGE: the api has changed"))
+	assert.False(t, conventional.HasBreakingChange("Some description"))
+	assert.False(t, conventional.HasBreakingChange("## Checklist\n\n- [ ] BREAKING CHANGE"))
+	assert.False(t, conventional.HasBreakingChange("### BREAKING CHANGES\n\nNone"))
+}
@@ -0,0 +1,208 @@
+// Package feed turns the entries of a changelog into an Atom or RSS feed so
+// that releases can be followed with a feed reader.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+)
+
+const (
+	FormatAtom = "atom"
+	FormatRSS  = "rss"
+)
+
+// Formats are the formats that a feed can be written in.
+var Formats = []string{FormatAtom, FormatRSS}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// item is a single entry of the changelog, ready to be written in any format.
+type item struct {
+	tag     string
+	link    string
+	date    time.Time
+	content string
+}
+
+// Write writes the entries of the changelog to writer as a feed in the given
+// format, latest first. Each item is identified by the URL of its tag so that
+// the ID does not change when the feed is generated again. Unreleased changes
+// are not included.
+func Write(writer io.Writer, format string, cl changelog.Changelog) error {
+	items, err := getItems(cl)
+	if err != nil {
+		return err
+	}
+
+	var feed interface{}
+	switch format {
+	case FormatAtom:
+		feed = newAtomFeed(cl, items)
+	case FormatRSS:
+		feed = newRSSFeed(cl, items)
+	default:
+		return fmt.Errorf("'%s' is not a valid feed format. Valid values are %s", format, strings.Join(Formats, ", "))
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writer, "\n")
+	return err
+}
+
+func getItems(cl changelog.Changelog) ([]item, error) {
+	var items []item
+	for _, e := range cl.GetEntries() {
+		content, err := renderContent(cl, e)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item{
+			tag:     e.Tag,
+			link:    writer.TagURL(cl, e.Tag),
+			date:    e.Date.UTC(),
+			content: content,
+		})
+	}
+
+	return items, nil
+}
+
+func renderContent(cl changelog.Changelog, e *entry.Entry) (string, error) {
+	var buf bytes.Buffer
+	if err := writer.WriteEntryHTML(&buf, cl, e); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func title(cl changelog.Changelog) string {
+	return fmt.Sprintf("%s/%s releases", cl.GetRepoOwner(), cl.GetRepoName())
+}
+
+func newAtomFeed(cl changelog.Changelog, items []item) atomFeed {
+	feed := atomFeed{
+		ID:     writer.RepoURL(cl),
+		Title:  title(cl),
+		Link:   atomLink{Href: writer.RepoURL(cl)},
+		Author: atomAuthor{Name: cl.GetRepoOwner()},
+	}
+
+	// The feed was last updated when the latest entry was released.
+	updated := time.Unix(0, 0).UTC()
+	if len(items) > 0 {
+		updated = items[0].date
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	for _, i := range items {
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:      i.link,
+			Title:   i.tag,
+			Updated: i.date.Format(time.RFC3339),
+			Link:    atomLink{Href: i.link},
+			Content: atomContent{Type: "html", Body: i.content},
+		})
+	}
+
+	return feed
+}
+
+func newRSSFeed(cl changelog.Changelog, items []item) rssFeed {
+	channel := rssChannel{
+		Title:       title(cl),
+		Link:        writer.RepoURL(cl),
+		Description: fmt.Sprintf("Releases of %s/%s from the changelog", cl.GetRepoOwner(), cl.GetRepoName()),
+	}
+
+	if len(items) > 0 {
+		channel.LastBuildDate = items[0].date.Format(time.RFC1123Z)
+	}
+
+	for _, i := range items {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       i.tag,
+			Link:        i.link,
+			GUID:        rssGUID{IsPermaLink: true, Value: i.link},
+			PubDate:     i.date.Format(time.RFC1123Z),
+			Description: i.content,
+		})
+	}
+
+	return rssFeed{Version: "2.0", Channel: channel}
+}
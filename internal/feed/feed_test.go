@@ -0,0 +1,132 @@
+package feed_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/feed"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupChangelog() changelog.Changelog {
+	cl := changelog.NewChangelog("repo-owner", "repo-name")
+
+	cl.Insert(entry.Entry{
+		Tag:     "v1.1.0",
+		PrevTag: "v1.0.0",
+		Date:    time.Date(2023, 2, 3, 0, 0, 0, 0, time.UTC),
+		Sections: []entry.Section{
+			{Name: "Fixed", Items: []entry.Item{{Text: "Fix <a> bug"}}},
+		},
+	})
+
+	cl.Insert(entry.Entry{
+		Tag:     "v1.0.0",
+		PrevTag: "v0.9.0",
+		Date:    time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Sections: []entry.Section{
+			{Name: "Added", Items: []entry.Item{{Title: "Add a feature", Number: 12, Author: "octocat"}}},
+		},
+	})
+
+	return cl
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	err := feed.Write(&buf, feed.FormatAtom, setupChangelog())
+	assert.NoError(t, err)
+
+	var parsed struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+
+	err = xml.Unmarshal(buf.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Equal(t, "https://github.com/repo-owner/repo-name", parsed.ID)
+	assert.Equal(t, "2023-02-03T00:00:00Z", parsed.Updated)
+
+	assert.Len(t, parsed.Entries, 2)
+	assert.Equal(t, "v1.1.0", parsed.Entries[0].Title)
+	assert.Equal(t, "https://github.com/repo-owner/repo-name/tree/v1.1.0", parsed.Entries[0].ID)
+	assert.Equal(t, "https://github.com/repo-owner/repo-name/tree/v1.1.0", parsed.Entries[0].Link.Href)
+	assert.Equal(t, "2023-02-03T00:00:00Z", parsed.Entries[0].Updated)
+	assert.Equal(t, "html", parsed.Entries[0].Content.Type)
+	assert.Contains(t, parsed.Entries[0].Content.Body, "<h3>Fixed</h3>")
+	assert.Contains(t, parsed.Entries[0].Content.Body, "<li>Fix &lt;a&gt; bug</li>")
+
+	assert.Equal(t, "v1.0.0", parsed.Entries[1].Title)
+	assert.Contains(t, parsed.Entries[1].Content.Body, `<a href="https://github.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0">Full Changelog</a>`)
+	assert.Contains(t, parsed.Entries[1].Content.Body, `Add a feature <a href="https://github.com/repo-owner/repo-name/pull/12">#12</a>`)
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	err := feed.Write(&buf, feed.FormatRSS, setupChangelog())
+	assert.NoError(t, err)
+
+	var parsed struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Link  string `xml:"link"`
+			Items []struct {
+				Title string `xml:"title"`
+				Link  string `xml:"link"`
+				GUID  struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate     string `xml:"pubDate"`
+				Description string `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	err = xml.Unmarshal(buf.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2.0", parsed.Version)
+	assert.Equal(t, "https://github.com/repo-owner/repo-name", parsed.Channel.Link)
+
+	assert.Len(t, parsed.Channel.Items, 2)
+	assert.Equal(t, "v1.1.0", parsed.Channel.Items[0].Title)
+	assert.Equal(t, "https://github.com/repo-owner/repo-name/tree/v1.1.0", parsed.Channel.Items[0].Link)
+	assert.Equal(t, "true", parsed.Channel.Items[0].GUID.IsPermaLink)
+	assert.Equal(t, "https://github.com/repo-owner/repo-name/tree/v1.1.0", parsed.Channel.Items[0].GUID.Value)
+	assert.Equal(t, "Fri, 03 Feb 2023 00:00:00 +0000", parsed.Channel.Items[0].PubDate)
+	assert.Contains(t, parsed.Channel.Items[0].Description, "<h3>Fixed</h3>")
+}
+
+func TestWriteIsStable(t *testing.T) {
+	var first, second bytes.Buffer
+	assert.NoError(t, feed.Write(&first, feed.FormatAtom, setupChangelog()))
+	assert.NoError(t, feed.Write(&second, feed.FormatAtom, setupChangelog()))
+
+	assert.Equal(t, first.String(), second.String())
+}
+
+func TestWriteReturnsAnErrorForAnUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := feed.Write(&buf, "json", setupChangelog())
+
+	assert.EqualError(t, err, "'json' is not a valid feed format. Valid values are atom, rss")
+}
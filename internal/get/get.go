@@ -20,28 +20,31 @@ func getTag(parsedChangelog changelog.Changelog, tag string) *entry.Entry {
 	return nil
 }
 
-func parseChangelog(fileName string) (changelog.Changelog, error) {
-	parser := parser.NewParser(fileName, "", "")
+func parseChangelog(fileName, repoOwner, repoName string) (changelog.Changelog, error) {
+	parser := parser.NewParser(fileName, repoOwner, repoName)
 	return parser.Parse()
 }
 
-func changelogWithSingleEntry(entry entry.Entry, repoName, repoOwner string) changelog.Changelog {
+func changelogWithSingleEntry(entry entry.Entry, repoName, repoOwner, repoHost string) changelog.Changelog {
 	// Isolate the entry
 	entry.Next = nil
 	if entry.Previous != nil {
-		entry.PrevTag = entry.Previous.Tag
+		if entry.PrevTag == "" {
+			entry.PrevTag = entry.Previous.Tag
+		}
 		entry.Previous = nil
 	}
 
 	cl := changelog.NewChangelog(repoOwner, repoName)
+	cl.SetRepoHost(repoHost)
 	cl.Insert(entry)
 	return cl
 }
 
 // GetVersion retrieves a local changelog, parses it and returns a string
 // containing only the specified version.
-func GetVersion(fileName string, tag string) (changelog.Changelog, error) {
-	parsedChangelog, err := parseChangelog(fileName)
+func GetVersion(fileName, tag, repoOwner, repoName string) (changelog.Changelog, error) {
+	parsedChangelog, err := parseChangelog(fileName, repoOwner, repoName)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +58,7 @@ func GetVersion(fileName string, tag string) (changelog.Changelog, error) {
 		*versionEntry,
 		parsedChangelog.GetRepoName(),
 		parsedChangelog.GetRepoOwner(),
+		parsedChangelog.GetRepoHost(),
 	)
 
 	return cl, nil
@@ -62,8 +66,8 @@ func GetVersion(fileName string, tag string) (changelog.Changelog, error) {
 
 // GetLatest retrieves a local changelog, parses it and returns a string
 // containing only the latest entry.
-func GetLatest(fileName string) (changelog.Changelog, error) {
-	parsedChangelog, err := parseChangelog(fileName)
+func GetLatest(fileName, repoOwner, repoName string) (changelog.Changelog, error) {
+	parsedChangelog, err := parseChangelog(fileName, repoOwner, repoName)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +77,7 @@ func GetLatest(fileName string) (changelog.Changelog, error) {
 		*latestEntry,
 		parsedChangelog.GetRepoName(),
 		parsedChangelog.GetRepoOwner(),
+		parsedChangelog.GetRepoHost(),
 	)
 
 	return cl, nil
@@ -80,11 +85,32 @@ func GetLatest(fileName string) (changelog.Changelog, error) {
 
 // GetAll retrieves a local changelog, parses it and returns a string
 // containing all entries
-func GetAll(fileName string) (changelog.Changelog, error) {
-	parsedChangelog, err := parseChangelog(fileName)
+func GetAll(fileName, repoOwner, repoName string) (changelog.Changelog, error) {
+	parsedChangelog, err := parseChangelog(fileName, repoOwner, repoName)
 	if err != nil {
 		return nil, err
 	}
 
 	return parsedChangelog, nil
 }
+
+// GetEach retrieves a local changelog, parses it and returns a changelog for
+// each entry, latest first.
+func GetEach(fileName, repoOwner, repoName string) ([]changelog.Changelog, error) {
+	parsedChangelog, err := parseChangelog(fileName, repoOwner, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	var changelogs []changelog.Changelog
+	for _, e := range parsedChangelog.GetEntries() {
+		changelogs = append(changelogs, changelogWithSingleEntry(
+			*e,
+			parsedChangelog.GetRepoName(),
+			parsedChangelog.GetRepoOwner(),
+			parsedChangelog.GetRepoHost(),
+		))
+	}
+
+	return changelogs, nil
+}
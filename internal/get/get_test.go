@@ -11,7 +11,7 @@ var fileName string = "CHANGELOG.md"
 var singleEntryFileName string = "single_CHANGELOG.md"
 
 func TestGetAll(t *testing.T) {
-	cl, err := get.GetAll(fileName)
+	cl, err := get.GetAll(fileName, "", "")
 
 	// Should not error
 	assert.Nil(t, err)
@@ -22,7 +22,7 @@ func TestGetAll(t *testing.T) {
 }
 
 func TestGetLatest(t *testing.T) {
-	cl, err := get.GetLatest(fileName)
+	cl, err := get.GetLatest(fileName, "", "")
 
 	// Should not error
 	assert.Nil(t, err)
@@ -34,7 +34,7 @@ func TestGetLatest(t *testing.T) {
 }
 
 func TestGetLatestWithNoPrevious(t *testing.T) {
-	cl, err := get.GetLatest(singleEntryFileName)
+	cl, err := get.GetLatest(singleEntryFileName, "", "")
 
 	// Should not error
 	assert.Nil(t, err)
@@ -42,12 +42,14 @@ func TestGetLatestWithNoPrevious(t *testing.T) {
 	// Should have 1 entry
 	count := len(cl.GetEntries())
 	assert.Equal(t, 1, count)
-	assert.Equal(t, "", cl.GetEntries()[0].PrevTag)
+
+	// The entry is compared with the commit from its Full Changelog link
+	assert.Equal(t, "42d4c93b23eaf307c5f9712f4c62014fe38332bd", cl.GetEntries()[0].PrevTag)
 }
 
 func TestGetVersionWithAValidVersion(t *testing.T) {
 	// Should not error when version is found
-	cl, err := get.GetVersion(fileName, "v0.9.0")
+	cl, err := get.GetVersion(fileName, "v0.9.0", "", "")
 	assert.Nil(t, err)
 
 	// Should have 1 entry
@@ -58,6 +60,23 @@ func TestGetVersionWithAValidVersion(t *testing.T) {
 
 func TestGetVersionWithAnInvalidVersion(t *testing.T) {
 	// Should error when version is not found
-	_, err := get.GetVersion(fileName, "v0.0.0")
+	_, err := get.GetVersion(fileName, "v0.0.0", "", "")
 	assert.NotNil(t, err)
 }
+
+func TestGetEach(t *testing.T) {
+	changelogs, err := get.GetEach(fileName, "", "")
+	assert.Nil(t, err)
+
+	all, err := get.GetAll(fileName, "", "")
+	assert.Nil(t, err)
+
+	// Should have a changelog with 1 entry for each entry
+	assert.Equal(t, len(all.GetEntries()), len(changelogs))
+	for i, cl := range changelogs {
+		assert.Len(t, cl.GetEntries(), 1)
+		assert.Equal(t, all.GetEntries()[i].Tag, cl.GetEntries()[0].Tag)
+	}
+
+	assert.Equal(t, "v0.13.0", changelogs[0].GetEntries()[0].PrevTag)
+}
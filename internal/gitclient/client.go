@@ -14,8 +14,38 @@ type GitClient interface {
 	GetFirstCommit() (string, error)
 	GetLastCommit() (string, error)
 	GetDateOfHash(hash string) (time.Time, error)
+	GetTags() ([]Tag, error)
+	GetTagsReachableFrom(ref string) ([]string, error)
+	GetCommitsBetweenDates(from, to time.Time) ([]Commit, error)
+	GetCommitsBetween(from, to string) ([]Commit, error)
+	GetFirstParentCommitsBetween(from, to string) ([]Commit, error)
+	GetChangedFiles(hash string) ([]string, error)
+	GetCommit(hash string) (Commit, error)
 }
 
+// Tag represents a tag in the local repository.
+type Tag struct {
+	Name string
+	Sha  string
+	Date time.Time
+}
+
+// Commit represents a single commit in the local repository.
+type Commit struct {
+	Sha         string
+	Author      string
+	AuthorEmail string
+	Date        time.Time
+	Parents     []string
+	Subject     string
+	Body        string
+}
+
+const (
+	fieldSeparator  = "\x1f"
+	recordSeparator = "\x1e"
+)
+
 type execContext = func(name string, arg ...string) *exec.Cmd
 
 type execOptions struct {
@@ -78,6 +108,188 @@ func (g git) GetDateOfHash(hash string) (time.Time, error) {
 	return time.ParseInLocation(time.RFC3339, date, time.Local)
 }
 
+// GetTags returns all annotated and lightweight tags in the local repository,
+// newest first. The Sha of each tag is the commit that it points to and the
+// Date is the tagger date for annotated tags or the committer date for
+// lightweight tags.
+func (g git) GetTags() ([]Tag, error) {
+	response, err := g.exec(execOptions{
+		args: []string{
+			"for-each-ref",
+			"--sort=-creatordate",
+			"--format=%(refname:strip=2)%1f%(objectname)%1f%(*objectname)%1f%(creatordate:iso-strict)",
+			"refs/tags",
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	for _, line := range strings.Split(response, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, fieldSeparator)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected tag format: %s", line)
+		}
+
+		// Annotated tags are peeled to the commit they point to.
+		sha := fields[1]
+		if fields[2] != "" {
+			sha = fields[2]
+		}
+
+		date, err := time.ParseInLocation(time.RFC3339, fields[3], time.Local)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, Tag{
+			Name: fields[0],
+			Sha:  sha,
+			Date: date,
+		})
+	}
+
+	return tags, nil
+}
+
+// GetTagsReachableFrom returns the names of the tags that point to commits
+// that are reachable from the given ref, such as a branch.
+func (g git) GetTagsReachableFrom(ref string) ([]string, error) {
+	response, err := g.exec(execOptions{
+		args: []string{"tag", "--merged", ref},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(response, "\n") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+func (g git) GetCommitsBetweenDates(from, to time.Time) ([]Commit, error) {
+	args := []string{}
+	if !from.IsZero() {
+		args = append(args, fmt.Sprintf("--since=%s", from.Format(time.RFC3339)))
+	}
+	args = append(args, fmt.Sprintf("--until=%s", to.Format(time.RFC3339)), "HEAD")
+
+	return g.log(args...)
+}
+
+// GetCommitsBetween returns the commits that are reachable from to but not
+// from from, equivalent to git rev-list from..to. If from is empty, every
+// commit reachable from to is returned.
+func (g git) GetCommitsBetween(from, to string) ([]Commit, error) {
+	revisionRange := to
+	if from != "" {
+		revisionRange = fmt.Sprintf("%s..%s", from, to)
+	}
+
+	return g.log(revisionRange)
+}
+
+// GetFirstParentCommitsBetween returns the commits between from and to in the
+// same way as GetCommitsBetween, but only follows the first parent of each
+// commit and leaves out merge commits. These are the commits that were made
+// directly on the branch, including squash merges, but not the commits of
+// branches that were merged in to it.
+func (g git) GetFirstParentCommitsBetween(from, to string) ([]Commit, error) {
+	revisionRange := to
+	if from != "" {
+		revisionRange = fmt.Sprintf("%s..%s", from, to)
+	}
+
+	return g.log("--first-parent", "--no-merges", revisionRange)
+}
+
+// GetCommit returns a single commit.
+func (g git) GetCommit(hash string) (Commit, error) {
+	commits, err := g.log("-1", hash)
+	if err != nil {
+		return Commit{}, err
+	}
+
+	if len(commits) == 0 {
+		return Commit{}, fmt.Errorf("the commit %s could not be found", hash)
+	}
+
+	return commits[0], nil
+}
+
+// GetChangedFiles returns the paths of the files that were changed by the
+// given commit. Merge commits are compared with their first parent.
+func (g git) GetChangedFiles(hash string) ([]string, error) {
+	response, err := g.exec(execOptions{
+		args: []string{"diff", "--name-only", fmt.Sprintf("%s^1", hash), hash},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range strings.Split(response, "\n") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func (g git) log(args ...string) ([]Commit, error) {
+	response, err := g.exec(execOptions{
+		args: append([]string{"log", "--format=%H%x1f%aN%x1f%aE%x1f%cI%x1f%P%x1f%s%x1f%b%x1e"}, args...),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(response, recordSeparator) {
+		record = strings.Trim(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, fieldSeparator)
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("unexpected commit format: %s", record)
+		}
+
+		date, err := time.ParseInLocation(time.RFC3339, fields[3], time.Local)
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, Commit{
+			Sha:         fields[0],
+			Author:      fields[1],
+			AuthorEmail: fields[2],
+			Date:        date,
+			Parents:     strings.Fields(fields[4]),
+			Subject:     fields[5],
+			Body:        strings.TrimSpace(fields[6]),
+		})
+	}
+
+	return commits, nil
+}
+
 func NewGitClient(cmdContext execContext) GitClient {
 	return git{
 		execContext: cmdContext,
@@ -110,3 +110,144 @@ func TestGetDateOfHashSuccess(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedDate, date)
 }
+
+func TestGetTagsSuccess(t *testing.T) {
+	mockOutput := "v2.0.0\x1f5f4d4b3cba5e6e0b8d5b6b1c6d2f0e0b7a2c1d3e\x1f0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1\x1f2022-04-18T19:31:31+00:00\n" +
+		"v1.0.0\x1f42d4c93b23eaf307c5f9712f4c62014fe38332bd\x1f\x1f2022-04-17T19:31:31+00:00"
+	defer safeSetMockOutput(mockOutput)()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	tags, err := gitClient.GetTags()
+	expectedDate, _ := time.ParseInLocation(time.RFC3339, "2022-04-18T19:31:31+00:00", time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "v2.0.0", tags[0].Name)
+	assert.Equal(t, "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", tags[0].Sha)
+	assert.Equal(t, expectedDate, tags[0].Date)
+	assert.Equal(t, "v1.0.0", tags[1].Name)
+	assert.Equal(t, "42d4c93b23eaf307c5f9712f4c62014fe38332bd", tags[1].Sha)
+}
+
+func TestGetTagsFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetTags()
+
+	assert.Error(t, err)
+}
+
+func TestGetCommitsBetweenDatesSuccess(t *testing.T) {
+	mockOutput := "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1\x1ftest-user\x1ftest-user@example.com\x1f2022-04-18T19:31:31+00:00\x1fb1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2 c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2\x1fMerge pull request #2 from test-user/branch\x1fthis is a test pr 2\n\x1e\n" +
+		"42d4c93b23eaf307c5f9712f4c62014fe38332bd\x1ftest-user\x1ftest-user@example.com\x1f2022-04-17T19:31:31+00:00\x1fb1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2\x1fthis is a test pr (#1)\x1f\x1e"
+	defer safeSetMockOutput(mockOutput)()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	commits, err := gitClient.GetCommitsBetweenDates(time.Time{}, time.Now())
+
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", commits[0].Sha)
+	assert.Equal(t, "Merge pull request #2 from test-user/branch", commits[0].Subject)
+	assert.Equal(t, []string{"b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"}, commits[0].Parents)
+	assert.Equal(t, "this is a test pr 2", commits[0].Body)
+	assert.Equal(t, "this is a test pr (#1)", commits[1].Subject)
+	assert.Equal(t, "", commits[1].Body)
+}
+
+func TestGetCommitsBetweenDatesFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetCommitsBetweenDates(time.Time{}, time.Now())
+
+	assert.Error(t, err)
+}
+
+func TestGetCommitsBetweenSuccess(t *testing.T) {
+	mockOutput := "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1\x1ftest-user\x1ftest-user@example.com\x1f2022-04-18T19:31:31+00:00\x1fb1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2\x1fthis is a test pr (#1)\x1f\x1e"
+	defer safeSetMockOutput(mockOutput)()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	commits, err := gitClient.GetCommitsBetween("v1.0.0", "v2.0.0")
+
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", commits[0].Sha)
+}
+
+func TestGetCommitsBetweenFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetCommitsBetween("v1.0.0", "v2.0.0")
+
+	assert.Error(t, err)
+}
+
+func TestGetFirstParentCommitsBetweenSuccess(t *testing.T) {
+	mockOutput := "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1\x1ftest-user\x1ftest-user@example.com\x1f2022-04-18T19:31:31+00:00\x1fb1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2\x1fhotfix: handle empty config\x1f\x1e"
+	defer safeSetMockOutput(mockOutput)()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	commits, err := gitClient.GetFirstParentCommitsBetween("", "v1.0.0")
+
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, "hotfix: handle empty config", commits[0].Subject)
+}
+
+func TestGetFirstParentCommitsBetweenFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetFirstParentCommitsBetween("v1.0.0", "v2.0.0")
+
+	assert.Error(t, err)
+}
+
+func TestGetCommitSuccess(t *testing.T) {
+	mockOutput := "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2\x1fTest User\x1f12345+test-user@users.noreply.github.com\x1f2022-04-18T19:31:31+00:00\x1fb1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2\x1fAdd a feature\x1f\x1e"
+	defer safeSetMockOutput(mockOutput)()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	commit, err := gitClient.GetCommit("c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345+test-user@users.noreply.github.com", commit.AuthorEmail)
+	assert.Equal(t, "Add a feature", commit.Subject)
+}
+
+func TestGetCommitFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetCommit("c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2")
+
+	assert.Error(t, err)
+}
+
+func TestGetChangedFilesSuccess(t *testing.T) {
+	defer safeSetMockOutput("services/api/main.go\nservices/api/go.mod\n")()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	files, err := gitClient.GetChangedFiles("0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"services/api/main.go", "services/api/go.mod"}, files)
+}
+
+func TestGetChangedFilesFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetChangedFiles("0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1")
+
+	assert.Error(t, err)
+}
+
+func TestGetTagsReachableFromSuccess(t *testing.T) {
+	defer safeSetMockOutput("v1.4.0\nv1.4.1\n")()
+
+	gitClient := gitclient.NewGitClient(fakeExecSuccess)
+	tags, err := gitClient.GetTagsReachableFrom("release/1.4")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.4.0", "v1.4.1"}, tags)
+}
+
+func TestGetTagsReachableFromFailure(t *testing.T) {
+	gitClient := gitclient.NewGitClient(fakeExecFailure)
+	_, err := gitClient.GetTagsReachableFrom("release/1.4")
+
+	assert.Error(t, err)
+}
@@ -1,4 +1,4 @@
-// Package githubclient is a wrapper around the githubv4 client.
+// Package githubclient is a wrapper around the go-gh GraphQL client.
 // It's purpose is to provide abstraction for some graphql queries
 // that retrieve data for the changelog.
 package githubclient
@@ -10,23 +10,27 @@ import (
 
 	"github.com/chelnak/gh-changelog/internal/utils"
 	"github.com/cli/go-gh/v2/pkg/api"
-	"github.com/shurcooL/githubv4"
 )
 
 type repoContext struct {
 	owner string
 	name  string
+	host  string
 }
 
 type GitHubClient interface {
 	GetTags() ([]Tag, error)
 	GetPullRequestsBetweenDates(from, to time.Time) ([]PullRequest, error)
+	GetFirstMergeDate(user string) (time.Time, error)
+	GetChangedFiles(number int) ([]string, error)
+	GetFirstCommit() (string, error)
 	GetRepoName() string
 	GetRepoOwner() string
+	GetRepoHost() string
 }
 
 type githubClient struct {
-	base        *githubv4.Client
+	base        *api.GraphQLClient
 	repoContext repoContext
 	httpContext context.Context
 }
@@ -39,17 +43,28 @@ func (client *githubClient) GetRepoOwner() string {
 	return client.repoContext.owner
 }
 
+func (client *githubClient) GetRepoHost() string {
+	return client.repoContext.host
+}
+
+// NewGitHubClient returns a GitHubClient for the repository in the current
+// directory.
 func NewGitHubClient() (GitHubClient, error) {
-	httpClient, err := api.DefaultHTTPClient()
+	currentRepository, err := utils.GetRepoContext()
 	if err != nil {
-		return nil, fmt.Errorf("could not create initial client: %s", err)
+		return nil, err
 	}
 
-	g := githubv4.NewClient(httpClient)
+	return NewGitHubClientForRepo(currentRepository)
+}
 
-	currentRepository, err := utils.GetRepoContext()
+// NewGitHubClientForRepo returns a GitHubClient for the given repository. The
+// repository does not need to be cloned locally.
+func NewGitHubClientForRepo(currentRepository utils.RepoContext) (GitHubClient, error) {
+	// go-gh resolves the GraphQL endpoint of the host in the same way as gh.
+	g, err := api.NewGraphQLClient(api.ClientOptions{Host: currentRepository.Host})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("could not create initial client: %s", err)
 	}
 
 	client := &githubClient{
@@ -57,6 +72,7 @@ func NewGitHubClient() (GitHubClient, error) {
 		repoContext: repoContext{
 			owner: currentRepository.Owner,
 			name:  currentRepository.Name,
+			host:  currentRepository.Host,
 		},
 		httpContext: context.Background(),
 	}
@@ -1,9 +1,12 @@
 package githubclient_test
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/chelnak/gh-changelog/internal/githubclient"
+	"github.com/chelnak/gh-changelog/internal/utils"
 	"github.com/chelnak/gh-changelog/mocks"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
@@ -60,3 +63,109 @@ func Test_GetTagsReturnsASliceOfTags(t *testing.T) {
 	assert.Equal(t, 2, len(tags))
 	assert.Equal(t, "v1.0.0", tags[0].Name)
 }
+
+func Test_ItUsesTheGraphQLEndpointOfTheHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		endpoint string
+	}{
+		{host: "github.com", endpoint: "https://api.github.com/graphql"},
+		{host: "ghes.example.com", endpoint: "https://ghes.example.com/api/graphql"},
+		{host: "github.localhost", endpoint: "http://api.github.localhost/graphql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder("POST", tt.endpoint,
+				NewJSONResponder(200, `{"data":{"search":{"nodes":[{"createdAt":"2023-01-01T00:00:00Z","mergedAt":"2023-01-02T00:00:00Z"}],"pageInfo":{"endCursor":"Y3Vyc29yOjE=","hasNextPage":false}}}}`),
+			)
+
+			t.Setenv("GH_TOKEN", "test-token")
+			t.Setenv("GH_ENTERPRISE_TOKEN", "test-token")
+			t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+			client, err := githubclient.NewGitHubClientForRepo(utils.RepoContext{Owner: "test", Name: "repo", Host: tt.host})
+			assert.NoError(t, err)
+
+			date, err := client.GetFirstMergeDate("octocat")
+			assert.NoError(t, err)
+			assert.Equal(t, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), date)
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func Test_GetChangedFilesReadsEveryPage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	first := httpmock.NewStringResponse(200, `{"data":{"repository":{"pullRequest":{"files":{"nodes":[{"path":"README.md"}],"pageInfo":{"endCursor":"Y3Vyc29yOjE=","hasNextPage":true}}}}}}`)
+	first.Header.Set("Content-Type", "application/json")
+	second := httpmock.NewStringResponse(200, `{"data":{"repository":{"pullRequest":{"files":{"nodes":[{"path":"services/api/main.go"}],"pageInfo":{"endCursor":"Y3Vyc29yOjI=","hasNextPage":false}}}}}}`)
+	second.Header.Set("Content-Type", "application/json")
+	httpmock.RegisterResponder("POST", "https://api.github.com/graphql", httpmock.ResponderFromMultipleResponses([]*http.Response{first, second}))
+
+	t.Setenv("GH_TOKEN", "test-token")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	client, err := githubclient.NewGitHubClientForRepo(utils.RepoContext{Owner: "test", Name: "repo", Host: "github.com"})
+	assert.NoError(t, err)
+
+	files, err := client.GetChangedFiles(12)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "services/api/main.go"}, files)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func Test_GetFirstMergeDateStopsAtTheEarliestMerge(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// The second pull request was created later but merged first. The third
+	// was created after that merge, so the next page is not needed.
+	httpmock.RegisterResponder("POST", "https://api.github.com/graphql",
+		NewJSONResponder(200, `{"data":{"search":{"nodes":[`+
+			`{"createdAt":"2023-01-01T00:00:00Z","mergedAt":"2023-03-01T00:00:00Z"},`+
+			`{"createdAt":"2023-01-05T00:00:00Z","mergedAt":"2023-01-10T00:00:00Z"},`+
+			`{"createdAt":"2023-01-11T00:00:00Z","mergedAt":"2023-01-12T00:00:00Z"}`+
+			`],"pageInfo":{"endCursor":"Y3Vyc29yOjM=","hasNextPage":true}}}}`),
+	)
+
+	t.Setenv("GH_TOKEN", "test-token")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	client, err := githubclient.NewGitHubClientForRepo(utils.RepoContext{Owner: "test", Name: "repo", Host: "github.com"})
+	assert.NoError(t, err)
+
+	date, err := client.GetFirstMergeDate("octocat")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC), date)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func Test_GetPullRequestsBetweenDatesReadsTheSubjectsOfTheCommits(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://api.github.com/graphql",
+		NewJSONResponder(200, `{"data":{"search":{"edges":[{"node":{`+
+			`"number":3,"title":"Fix a bug","author":{"__typename":"User","login":"octocat"},`+
+			`"mergeCommit":{"oid":"d4a1c2b"},`+
+			`"commits":{"nodes":[{"commit":{"messageHeadline":"Add a test for the bug"}},{"commit":{"messageHeadline":"Fix a bug"}}]}`+
+			`}}],"pageInfo":{"endCursor":"Y3Vyc29yOjE=","hasNextPage":false}}}}`),
+	)
+
+	t.Setenv("GH_TOKEN", "test-token")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	client, err := githubclient.NewGitHubClientForRepo(utils.RepoContext{Owner: "test", Name: "repo", Host: "github.com"})
+	assert.NoError(t, err)
+
+	pullRequests, err := client.GetPullRequestsBetweenDates(time.Time{}, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	assert.Equal(t, "d4a1c2b", pullRequests[0].MergeCommitSha)
+	assert.Equal(t, []string{"Add a test for the bug", "Fix a bug"}, pullRequests[0].CommitSubjects)
+}
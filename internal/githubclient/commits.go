@@ -0,0 +1,68 @@
+package githubclient
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+type FirstCommitQuery struct {
+	Repository struct {
+		DefaultBranchRef struct {
+			Target struct {
+				Commit struct {
+					History struct {
+						TotalCount int
+						Nodes      []struct {
+							Oid string
+						}
+						PageInfo struct {
+							StartCursor githubv4.String
+						}
+					} `graphql:"history(first: 1, after: $cursor)"`
+				} `graphql:"... on Commit"`
+			}
+		}
+	} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+}
+
+// GetFirstCommit returns the hash of the first commit on the default branch.
+// The history is ordered newest first, so the cursor of the first page is used
+// to jump straight to the last commit rather than paging through the history.
+func (client *githubClient) GetFirstCommit() (string, error) {
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(client.repoContext.owner),
+		"repositoryName":  githubv4.String(client.repoContext.name),
+		"cursor":          (*githubv4.String)(nil),
+	}
+
+	var query FirstCommitQuery
+	if err := client.base.QueryWithContext(client.httpContext, "FirstCommit", &query, variables); err != nil {
+		return "", fmt.Errorf("error getting the first commit: %w", err)
+	}
+
+	history := query.Repository.DefaultBranchRef.Target.Commit.History
+	if history.TotalCount <= 1 {
+		if len(history.Nodes) == 0 {
+			return "", fmt.Errorf("the repository %s/%s does not have any commits", client.repoContext.owner, client.repoContext.name)
+		}
+
+		return history.Nodes[0].Oid, nil
+	}
+
+	// Cursors have the format "<sha> <offset>".
+	sha := strings.Fields(string(history.PageInfo.StartCursor))[0]
+	variables["cursor"] = githubv4.String(fmt.Sprintf("%s %d", sha, history.TotalCount-2))
+
+	if err := client.base.QueryWithContext(client.httpContext, "FirstCommit", &query, variables); err != nil {
+		return "", fmt.Errorf("error getting the first commit: %w", err)
+	}
+
+	nodes := query.Repository.DefaultBranchRef.Target.Commit.History.Nodes
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("the first commit of %s/%s could not be found", client.repoContext.owner, client.repoContext.name)
+	}
+
+	return nodes[0].Oid, nil
+}
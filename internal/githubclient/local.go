@@ -0,0 +1,220 @@
+package githubclient
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/gitclient"
+	"github.com/chelnak/gh-changelog/internal/utils"
+)
+
+var (
+	// Merge pull request #123 from user/branch
+	mergeCommitRegex = regexp.MustCompile(`^Merge pull request #(\d+) from ([^/\s]+)/\S+`)
+
+	// A pull request title (#123)
+	squashCommitRegex = regexp.MustCompile(`^(.+) \(#(\d+)\)$`)
+
+	// 12345+user@users.noreply.github.com or user@users.noreply.ghes.example.com
+	noReplyEmailRegex = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.\S+$`)
+)
+
+// localClient is an implementation of GitHubClient that is backed by the
+// local git repository. It does not require authentication or network access.
+// Pull requests are derived from merge and squash commit messages, so
+// any label information is unavailable.
+type localClient struct {
+	git         gitclient.GitClient
+	repoContext repoContext
+	firstMerges map[string]time.Time // keyed by lower case login, read once
+}
+
+func (client *localClient) GetRepoName() string {
+	return client.repoContext.name
+}
+
+func (client *localClient) GetRepoOwner() string {
+	return client.repoContext.owner
+}
+
+func (client *localClient) GetRepoHost() string {
+	return client.repoContext.host
+}
+
+func (client *localClient) GetFirstCommit() (string, error) {
+	return client.git.GetFirstCommit()
+}
+
+func (client *localClient) GetTags() ([]Tag, error) {
+	localTags, err := client.git.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	for _, tag := range localTags {
+		tags = append(tags, Tag{
+			Name: tag.Name,
+			Sha:  tag.Sha,
+			Date: tag.Date,
+		})
+	}
+
+	return tags, nil
+}
+
+func (client *localClient) GetPullRequestsBetweenDates(fromDate, toDate time.Time) ([]PullRequest, error) {
+	commits, err := client.git.GetCommitsBetweenDates(fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	bySha := make(map[string]gitclient.Commit)
+	for _, commit := range commits {
+		bySha[commit.Sha] = commit
+	}
+
+	var pullRequests []PullRequest
+	for _, commit := range commits {
+		pr, ok := pullRequestFromCommit(commit)
+		if !ok {
+			continue
+		}
+
+		// The branch in the subject of a merge commit belongs to the owner of
+		// the head repository, which is the organisation for branches in the
+		// same repository. The author of the last commit on the branch is
+		// used instead.
+		if mergeCommitRegex.MatchString(commit.Subject) && len(commit.Parents) > 1 {
+			head, ok := bySha[commit.Parents[1]]
+			if !ok {
+				head, err = client.git.GetCommit(commit.Parents[1])
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			pr.User = userFromCommit(head)
+		}
+
+		pr.IsBot = strings.HasSuffix(pr.User, "[bot]")
+		pullRequests = append(pullRequests, pr)
+	}
+
+	return pullRequests, nil
+}
+
+// GetFirstMergeDate returns the date of the first merge or squash commit for
+// a pull request by the user, or the zero time if there is none. The history
+// is only read the first time that it is called.
+func (client *localClient) GetFirstMergeDate(user string) (time.Time, error) {
+	if client.firstMerges == nil {
+		pullRequests, err := client.GetPullRequestsBetweenDates(time.Time{}, time.Now())
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		client.firstMerges = make(map[string]time.Time)
+		for _, pr := range pullRequests {
+			login := strings.ToLower(pr.User)
+			if first, ok := client.firstMerges[login]; !ok || pr.MergedAt.Before(first) {
+				client.firstMerges[login] = pr.MergedAt
+			}
+		}
+	}
+
+	return client.firstMerges[strings.ToLower(user)], nil
+}
+
+// GetChangedFiles returns the files that were changed by the merge or squash
+// commit of the pull request.
+func (client *localClient) GetChangedFiles(number int) ([]string, error) {
+	pullRequests, err := client.GetPullRequestsBetweenDates(time.Time{}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pr := range pullRequests {
+		if pr.Number == number {
+			return client.git.GetChangedFiles(pr.MergeCommitSha)
+		}
+	}
+
+	return nil, fmt.Errorf("a merge commit for pull request #%d could not be found", number)
+}
+
+// pullRequestFromCommit attempts to build a PullRequest from a merge commit
+// created by GitHub or from a squash merge commit with a (#123) suffix.
+func pullRequestFromCommit(commit gitclient.Commit) (PullRequest, bool) {
+	if match := mergeCommitRegex.FindStringSubmatch(commit.Subject); match != nil {
+		number, err := strconv.Atoi(match[1])
+		if err != nil {
+			return PullRequest{}, false
+		}
+
+		title := strings.TrimSpace(strings.Split(commit.Body, "\n")[0])
+		if title == "" {
+			title = commit.Subject
+		}
+
+		return PullRequest{
+			Number:         number,
+			Title:          title,
+			Body:           commit.Body,
+			User:           match[2],
+			MergeCommitSha: commit.Sha,
+			MergedAt:       commit.Date,
+		}, true
+	}
+
+	if match := squashCommitRegex.FindStringSubmatch(commit.Subject); match != nil {
+		number, err := strconv.Atoi(match[2])
+		if err != nil {
+			return PullRequest{}, false
+		}
+
+		return PullRequest{
+			Number:         number,
+			Title:          match[1],
+			Body:           commit.Body,
+			User:           userFromCommit(commit),
+			MergeCommitSha: commit.Sha,
+			MergedAt:       commit.Date,
+		}, true
+	}
+
+	return PullRequest{}, false
+}
+
+// userFromCommit returns the GitHub login of the commit author when it can be
+// derived from a noreply address, otherwise the author name is used.
+func userFromCommit(commit gitclient.Commit) string {
+	if match := noReplyEmailRegex.FindStringSubmatch(commit.AuthorEmail); match != nil {
+		return match[1]
+	}
+
+	return commit.Author
+}
+
+// NewLocalClient returns a GitHubClient that reads tags and pull requests
+// from the local git repository.
+func NewLocalClient(git gitclient.GitClient) (GitHubClient, error) {
+	currentRepository, err := utils.GetRepoContext()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &localClient{
+		git: git,
+		repoContext: repoContext{
+			owner: currentRepository.Owner,
+			name:  currentRepository.Name,
+			host:  currentRepository.Host,
+		},
+	}
+
+	return client, nil
+}
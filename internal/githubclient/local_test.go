@@ -0,0 +1,197 @@
+package githubclient_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/gitclient"
+	"github.com/chelnak/gh-changelog/internal/githubclient"
+	"github.com/chelnak/gh-changelog/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_LocalClientReturnsTags(t *testing.T) {
+	t.Setenv("GH_REPO", "test/repo")
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: time.Time{}},
+	}, nil)
+
+	client, err := githubclient.NewLocalClient(mockGitClient)
+	assert.NoError(t, err)
+
+	tags, err := client.GetTags()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", client.GetRepoOwner())
+	assert.Equal(t, "repo", client.GetRepoName())
+	assert.Len(t, tags, 1)
+	assert.Equal(t, "v1.0.0", tags[0].Name)
+	assert.Equal(t, "42d4c93b23eaf307c5f9712f4c62014fe38332bd", tags[0].Sha)
+}
+
+func Test_LocalClientDerivesPullRequestsFromCommits(t *testing.T) {
+	t.Setenv("GH_REPO", "test/repo")
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetCommitsBetweenDates", time.Time{}, time.Time{}).Return([]gitclient.Commit{
+		{
+			Sha:     "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1",
+			Author:  "Test User",
+			Subject: "Merge pull request #3 from fork-user/feature-branch",
+			Body:    "Add a new feature\n\nWith a longer description",
+		},
+		{
+			Sha:         "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Author:      "Test User",
+			AuthorEmail: "12345+test-user@users.noreply.github.com",
+			Subject:     "Fix a bug (#2)",
+		},
+		{
+			Sha:         "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+			Author:      "Test User",
+			AuthorEmail: "test-user@example.com",
+			Subject:     "Update docs (#1)",
+		},
+		{
+			Sha:     "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+			Author:  "Test User",
+			Subject: "A commit pushed directly",
+		},
+	}, nil)
+
+	client, err := githubclient.NewLocalClient(mockGitClient)
+	assert.NoError(t, err)
+
+	pullRequests, err := client.GetPullRequestsBetweenDates(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 3)
+
+	assert.Equal(t, 3, pullRequests[0].Number)
+	assert.Equal(t, "Add a new feature", pullRequests[0].Title)
+	assert.Equal(t, "fork-user", pullRequests[0].User)
+
+	assert.Equal(t, 2, pullRequests[1].Number)
+	assert.Equal(t, "Fix a bug", pullRequests[1].Title)
+	assert.Equal(t, "test-user", pullRequests[1].User)
+
+	assert.Equal(t, 1, pullRequests[2].Number)
+	assert.Equal(t, "Update docs", pullRequests[2].Title)
+	assert.Equal(t, "Test User", pullRequests[2].User)
+}
+
+func Test_LocalClientReturnsTheHostOfTheRepository(t *testing.T) {
+	t.Setenv("GH_REPO", "ghes.example.com/test/repo")
+
+	client, err := githubclient.NewLocalClient(&mocks.GitClient{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ghes.example.com", client.GetRepoHost())
+	assert.Equal(t, "test", client.GetRepoOwner())
+	assert.Equal(t, "repo", client.GetRepoName())
+}
+
+func Test_LocalClientReturnsTheFirstMergeDateOfAUser(t *testing.T) {
+	t.Setenv("GH_REPO", "test/repo")
+
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetCommitsBetweenDates", time.Time{}, mock.Anything).Return([]gitclient.Commit{
+		{
+			Sha:     "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1",
+			Subject: "Merge pull request #3 from fork-user/feature-branch",
+			Date:    second,
+		},
+		{
+			Sha:     "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Subject: "Merge pull request #2 from Fork-User/another-branch",
+			Date:    first,
+		},
+	}, nil).Once()
+
+	client, err := githubclient.NewLocalClient(mockGitClient)
+	assert.NoError(t, err)
+
+	date, err := client.GetFirstMergeDate("fork-user")
+	assert.NoError(t, err)
+	assert.Equal(t, first, date)
+
+	// The history is only read once.
+	date, err = client.GetFirstMergeDate("new-user")
+	assert.NoError(t, err)
+	assert.True(t, date.IsZero())
+	mockGitClient.AssertExpectations(t)
+}
+
+func Test_LocalClientReturnsTheChangedFilesOfAPullRequest(t *testing.T) {
+	t.Setenv("GH_REPO", "test/repo")
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetCommitsBetweenDates", time.Time{}, mock.Anything).Return([]gitclient.Commit{
+		{Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Subject: "Fix a bug (#2)"},
+	}, nil)
+	mockGitClient.On("GetChangedFiles", "42d4c93b23eaf307c5f9712f4c62014fe38332bd").Return([]string{"main.go"}, nil)
+
+	client, err := githubclient.NewLocalClient(mockGitClient)
+	assert.NoError(t, err)
+
+	files, err := client.GetChangedFiles(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"main.go"}, files)
+
+	_, err = client.GetChangedFiles(3)
+	assert.EqualError(t, err, "a merge commit for pull request #3 could not be found")
+}
+
+func Test_LocalClientTakesTheAuthorOfAMergeFromTheBranch(t *testing.T) {
+	t.Setenv("GH_REPO", "test/repo")
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetCommitsBetweenDates", time.Time{}, time.Time{}).Return([]gitclient.Commit{
+		{
+			Sha:     "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1",
+			Author:  "GitHub",
+			Parents: []string{"42d4c93b23eaf307c5f9712f4c62014fe38332bd", "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"},
+			Subject: "Merge pull request #5 from test/another-feature",
+			Body:    "Add another feature",
+		},
+		{
+			Sha:     "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Author:  "GitHub",
+			Parents: []string{"a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"},
+			Subject: "Merge pull request #4 from test/feature",
+			Body:    "Add a feature",
+		},
+		{
+			Sha:         "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+			Author:      "Octo Cat",
+			AuthorEmail: "12345+octocat@users.noreply.github.com",
+			Parents:     []string{"a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"},
+			Subject:     "Add a feature",
+		},
+	}, nil)
+	mockGitClient.On("GetCommit", "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2").Return(gitclient.Commit{
+		Sha:         "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+		Author:      "Jane Doe",
+		AuthorEmail: "jane@example.com",
+	}, nil)
+
+	client, err := githubclient.NewLocalClient(mockGitClient)
+	assert.NoError(t, err)
+
+	pullRequests, err := client.GetPullRequestsBetweenDates(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 2)
+
+	// The branches are in the same repository, so the owner in the subject is
+	// not the author.
+	assert.Equal(t, 5, pullRequests[0].Number)
+	assert.Equal(t, "Jane Doe", pullRequests[0].User)
+	assert.Equal(t, 4, pullRequests[1].Number)
+	assert.Equal(t, "octocat", pullRequests[1].User)
+	mockGitClient.AssertExpectations(t)
+}
@@ -14,14 +14,28 @@ type PullRequestLabel struct {
 type PullRequestEdge struct {
 	Node struct {
 		PullRequest struct {
-			Number int
-			Title  string
-			Author struct {
-				Login string
+			Number   int
+			Title    string
+			Body     string
+			URL      string `graphql:"url"`
+			MergedAt time.Time
+			Author   struct {
+				TypeName string `graphql:"__typename"`
+				Login    string
 			}
 			Labels struct {
 				Nodes []PullRequestLabel
 			} `graphql:"labels(first: 100)"`
+			MergeCommit struct {
+				Oid string
+			}
+			Commits struct {
+				Nodes []struct {
+					Commit struct {
+						MessageHeadline string
+					}
+				}
+			} `graphql:"commits(first: 100)"`
 		} `graphql:"... on PullRequest"`
 	}
 }
@@ -36,11 +50,48 @@ type PullRequestSearchQuery struct {
 	} `graphql:"search(query: $query, type: ISSUE, first: 100, after: $cursor)"`
 }
 
+type PullRequestFirstMergeQuery struct {
+	Search struct {
+		Nodes []struct {
+			PullRequest struct {
+				CreatedAt time.Time
+				MergedAt  time.Time
+			} `graphql:"... on PullRequest"`
+		}
+		PageInfo struct {
+			EndCursor   githubv4.String
+			HasNextPage bool
+		}
+	} `graphql:"search(query: $query, type: ISSUE, first: 100, after: $cursor)"`
+}
+
+type PullRequestFilesQuery struct {
+	Repository struct {
+		PullRequest struct {
+			Files struct {
+				Nodes []struct {
+					Path string
+				}
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage bool
+				}
+			} `graphql:"files(first: 100, after: $cursor)"`
+		} `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+}
+
 type PullRequest struct {
-	Number int
-	Title  string
-	User   string
-	Labels []PullRequestLabel
+	Number         int
+	Title          string
+	Body           string
+	URL            string
+	User           string
+	IsBot          bool
+	Labels         []PullRequestLabel
+	MergeCommitSha string
+	MergedAt       time.Time
+	CommitSubjects []string // the subjects of the first 100 commits of the pull request
 }
 
 func (client *githubClient) GetPullRequestsBetweenDates(fromDate, toDate time.Time) ([]PullRequest, error) {
@@ -62,7 +113,7 @@ func (client *githubClient) GetPullRequestsBetweenDates(fromDate, toDate time.Ti
 	var edges []PullRequestEdge
 
 	for {
-		err := client.base.Query(client.httpContext, &pullRequestSearchQuery, variables)
+		err := client.base.QueryWithContext(client.httpContext, "PullRequestSearch", &pullRequestSearchQuery, variables)
 		if err != nil {
 			return nil, err
 		}
@@ -76,13 +127,104 @@ func (client *githubClient) GetPullRequestsBetweenDates(fromDate, toDate time.Ti
 	}
 
 	for _, edge := range edges {
+		var subjects []string
+		for _, node := range edge.Node.PullRequest.Commits.Nodes {
+			subjects = append(subjects, node.Commit.MessageHeadline)
+		}
+
 		pullRequests = append(pullRequests, PullRequest{
-			Number: edge.Node.PullRequest.Number,
-			Title:  edge.Node.PullRequest.Title,
-			User:   edge.Node.PullRequest.Author.Login,
-			Labels: edge.Node.PullRequest.Labels.Nodes,
+			Number:         edge.Node.PullRequest.Number,
+			Title:          edge.Node.PullRequest.Title,
+			Body:           edge.Node.PullRequest.Body,
+			URL:            edge.Node.PullRequest.URL,
+			User:           edge.Node.PullRequest.Author.Login,
+			IsBot:          edge.Node.PullRequest.Author.TypeName == "Bot",
+			Labels:         edge.Node.PullRequest.Labels.Nodes,
+			MergeCommitSha: edge.Node.PullRequest.MergeCommit.Oid,
+			MergedAt:       edge.Node.PullRequest.MergedAt,
+			CommitSubjects: subjects,
 		})
 	}
 
 	return pullRequests, nil
 }
+
+// GetFirstMergeDate returns the date that the first pull request by the user
+// was merged into the repository, or the zero time if none were merged.
+// Search results can not be sorted by merge date, so they are read in the
+// order they were created until a pull request was created after the earliest
+// merge that has been found.
+func (client *githubClient) GetFirstMergeDate(user string) (time.Time, error) {
+	variables := map[string]interface{}{
+		"query": githubv4.String(
+			fmt.Sprintf(
+				`repo:%s/%s is:pr is:merged author:%s sort:created-asc`,
+				client.repoContext.owner,
+				client.repoContext.name,
+				user,
+			),
+		),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	var pullRequestFirstMergeQuery PullRequestFirstMergeQuery
+	var first time.Time
+
+	for {
+		err := client.base.QueryWithContext(client.httpContext, "PullRequestFirstMerge", &pullRequestFirstMergeQuery, variables)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		for _, node := range pullRequestFirstMergeQuery.Search.Nodes {
+			pr := node.PullRequest
+			if !first.IsZero() && pr.CreatedAt.After(first) {
+				return first, nil
+			}
+
+			if first.IsZero() || pr.MergedAt.Before(first) {
+				first = pr.MergedAt
+			}
+		}
+
+		if !pullRequestFirstMergeQuery.Search.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = pullRequestFirstMergeQuery.Search.PageInfo.EndCursor
+	}
+
+	return first, nil
+}
+
+// GetChangedFiles returns the paths of the files that were changed by the
+// pull request.
+func (client *githubClient) GetChangedFiles(number int) ([]string, error) {
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(client.repoContext.owner),
+		"repositoryName":  githubv4.String(client.repoContext.name),
+		"number":          githubv4.Int(number),
+		"cursor":          (*githubv4.String)(nil),
+	}
+
+	var pullRequestFilesQuery PullRequestFilesQuery
+	var files []string
+
+	for {
+		err := client.base.QueryWithContext(client.httpContext, "PullRequestFiles", &pullRequestFilesQuery, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		changed := pullRequestFilesQuery.Repository.PullRequest.Files
+		for _, file := range changed.Nodes {
+			files = append(files, file.Path)
+		}
+
+		if !changed.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = changed.PageInfo.EndCursor
+	}
+
+	return files, nil
+}
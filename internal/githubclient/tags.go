@@ -57,7 +57,7 @@ func (client *githubClient) GetTags() ([]Tag, error) {
 	var nodes []RefNode
 
 	for {
-		err := client.base.Query(client.httpContext, &tagQuery, variables)
+		err := client.base.QueryWithContext(client.httpContext, "Tags", &tagQuery, variables)
 		if err != nil {
 			return nil, fmt.Errorf("error getting tags: %w", err)
 		}
@@ -0,0 +1,341 @@
+// Package lint checks a changelog against the Keep a Changelog conventions
+// that are followed when a changelog is created.
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/version"
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"github.com/chelnak/gh-changelog/pkg/parser"
+)
+
+// ## Unreleased or ## [Unreleased](https://github.com/owner/repo/compare/v1.0.0...HEAD)
+var unreleasedHeadingRegex = regexp.MustCompile(`(?m)^## \[?Unreleased\]?`)
+
+// The sections that are defined by Keep a Changelog. Other sections are
+// allowed when they are configured.
+var standardSections = []string{"added", "changed", "deprecated", "removed", "fixed", "security", "other"}
+
+// Severity describes how serious a problem is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is a single issue that was found in the changelog. Line and Column
+// are 1-based and are zero when the problem does not relate to a single line.
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Lint parses the changelog at the given path and returns the problems that
+// were found. When repoOwner and repoName are empty, the repository in the
+// current directory is used. The tagPrefix is removed from versions before
+// they are compared, so that the changelog of a package can be checked.
+func Lint(fileName, repoOwner, repoName, tagPrefix string) ([]Problem, error) {
+	data, err := os.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.NewParser(fileName, repoOwner, repoName)
+	cl, err := p.ParseAll()
+
+	var parseErrors parser.ParseErrors
+	if err != nil && !errors.As(err, &parseErrors) {
+		return nil, err
+	}
+
+	var problems []Problem
+	invalidLines := make(map[int]bool)
+	for _, parseError := range parseErrors {
+		invalidLines[parseError.Line] = true
+
+		message := parseError.Message
+		if parseError.Text != "" {
+			message = fmt.Sprintf("%s %q", message, parseError.Text)
+		}
+
+		if parseError.Hint != "" {
+			message = fmt.Sprintf("%s (%s)", message, parseError.Hint)
+		}
+
+		problems = append(problems, Problem{
+			Severity: SeverityError,
+			Line:     parseError.Line,
+			Column:   parseError.Column,
+			Message:  message,
+		})
+	}
+
+	if !unreleasedHeadingRegex.Match(data) {
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Message:  "the changelog does not have an Unreleased section",
+		})
+	}
+
+	problems = append(problems, checkVersions(cl, tagPrefix)...)
+
+	for _, e := range cl.GetEntries() {
+		// An invalid date has already been reported by the parser.
+		if e.Date.IsZero() && !invalidLines[e.Line] {
+			problems = append(problems, Problem{
+				Severity: SeverityError,
+				Line:     e.Line,
+				Version:  e.Tag,
+				Message:  fmt.Sprintf("%s does not have a date", e.Tag),
+			})
+		}
+
+		problems = append(problems, checkCompareLink(e, writer.RepoURL(cl), tagPrefix)...)
+		problems = append(problems, checkSections(e)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems, nil
+}
+
+// checkVersions reports versions that are not semantic versions, that are
+// listed more than once or that are not in descending order.
+func checkVersions(cl changelog.Changelog, tagPrefix string) []Problem {
+	var problems []Problem
+
+	seen := make(map[string]*entry.Entry)
+	for _, e := range cl.GetEntries() {
+		v, err := version.NormalizeVersion(strings.TrimPrefix(e.Tag, tagPrefix))
+		if err != nil {
+			problems = append(problems, Problem{
+				Severity: SeverityWarning,
+				Line:     e.Line,
+				Version:  e.Tag,
+				Message:  fmt.Sprintf("%s is not a semantic version", e.Tag),
+			})
+			continue
+		}
+
+		if first, ok := seen[v.String()]; ok {
+			problems = append(problems, Problem{
+				Severity: SeverityError,
+				Line:     e.Line,
+				Version:  e.Tag,
+				Message:  fmt.Sprintf("%s is listed more than once (first on line %d)", e.Tag, first.Line),
+			})
+			continue
+		}
+		seen[v.String()] = e
+
+		if e.Previous == nil {
+			continue
+		}
+
+		previous, err := version.NormalizeVersion(strings.TrimPrefix(e.Previous.Tag, tagPrefix))
+		if err != nil {
+			continue
+		}
+
+		if !v.GreaterThan(previous) && !v.Equal(previous) {
+			problems = append(problems, Problem{
+				Severity: SeverityError,
+				Line:     e.Line,
+				Version:  e.Tag,
+				Message:  fmt.Sprintf("%s is listed before %s but is an older version", e.Tag, e.Previous.Tag),
+			})
+		}
+	}
+
+	return problems
+}
+
+// checkCompareLink reports entries that do not have a Full Changelog link
+// that compares them with the previous version in the repository at repoURL.
+// A stable version may also be compared with the version before its
+// pre-releases when they are folded.
+func checkCompareLink(e *entry.Entry, repoURL, tagPrefix string) []Problem {
+	var problems []Problem
+
+	if e.CompareURL != "" && !strings.HasPrefix(e.CompareURL, repoURL+"/compare/") {
+		problems = append(problems, Problem{
+			Severity: SeverityError,
+			Line:     e.Line,
+			Version:  e.Tag,
+			Message:  fmt.Sprintf("the Full Changelog link of %s is not in %s", e.Tag, repoURL),
+		})
+	}
+
+	switch {
+	case e.PrevTag == "":
+		problems = append(problems, Problem{
+			Severity: SeverityError,
+			Line:     e.Line,
+			Version:  e.Tag,
+			Message:  fmt.Sprintf("%s does not have a Full Changelog link that compares it with the previous version", e.Tag),
+		})
+	case e.Previous != nil && e.PrevTag != e.Previous.Tag && e.PrevTag != previousRelease(e, tagPrefix):
+		problems = append(problems, Problem{
+			Severity: SeverityError,
+			Line:     e.Line,
+			Version:  e.Tag,
+			Message:  fmt.Sprintf("the Full Changelog link of %s compares it with %s instead of %s", e.Tag, e.PrevTag, e.Previous.Tag),
+		})
+	}
+
+	return problems
+}
+
+// previousRelease returns the tag of the first older entry that is not a
+// pre-release of the given entry.
+func previousRelease(e *entry.Entry, tagPrefix string) string {
+	previous := e.Previous
+	for previous != nil && version.IsPrereleaseOf(strings.TrimPrefix(previous.Tag, tagPrefix), strings.TrimPrefix(e.Tag, tagPrefix)) {
+		previous = previous.Previous
+	}
+
+	if previous == nil {
+		return ""
+	}
+
+	return previous.Tag
+}
+
+// checkSections reports sections that are empty or that are not known.
+func checkSections(e *entry.Entry) []Problem {
+	var problems []Problem
+
+	known := knownSections()
+	for _, s := range e.Sections {
+		if !known[normalizeSectionName(s.Name)] {
+			problems = append(problems, Problem{
+				Severity: SeverityWarning,
+				Line:     s.Line,
+				Version:  e.Tag,
+				Message:  fmt.Sprintf("%s is not a known section", s.Name),
+			})
+		}
+
+		if len(s.Items) == 0 && s.Description == "" {
+			problems = append(problems, Problem{
+				Severity: SeverityWarning,
+				Line:     s.Line,
+				Version:  e.Tag,
+				Message:  fmt.Sprintf("the %s section is empty", s.Name),
+			})
+		}
+	}
+
+	return problems
+}
+
+// knownSections returns the sections defined by Keep a Changelog and any
+// sections that have been configured.
+func knownSections() map[string]bool {
+	known := make(map[string]bool)
+	for _, name := range standardSections {
+		known[name] = true
+	}
+
+	for name := range configuration.Config.Sections {
+		known[normalizeSectionName(name)] = true
+	}
+
+	for _, name := range configuration.Config.SectionOrder {
+		known[normalizeSectionName(name)] = true
+	}
+
+	for _, name := range configuration.Config.ConventionalCommits.Types {
+		known[normalizeSectionName(name)] = true
+	}
+
+	return known
+}
+
+func normalizeSectionName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", " "))
+}
+
+// HasErrors returns true if any of the problems is an error. If strict is
+// true, warnings are also treated as errors.
+func HasErrors(problems []Problem, strict bool) bool {
+	for _, problem := range problems {
+		if problem.Severity == SeverityError || strict {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteText writes the problems to the given writer in a format that is
+// similar to the output of a compiler.
+func WriteText(w io.Writer, fileName string, problems []Problem) error {
+	var errorCount, warningCount int
+	for _, problem := range problems {
+		location := fileName
+		if problem.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, problem.Line)
+		}
+
+		if problem.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, problem.Column)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s: %s\n", location, problem.Severity, problem.Message); err != nil {
+			return err
+		}
+
+		if problem.Severity == SeverityError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+
+	if len(problems) == 0 {
+		_, err := fmt.Fprintf(w, "No problems found in %s\n", fileName)
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nFound %d error(s) and %d warning(s) in %s\n", errorCount, warningCount, fileName)
+	return err
+}
+
+// WriteJSON writes the problems to the given writer as JSON.
+func WriteJSON(w io.Writer, fileName string, problems []Problem) error {
+	if problems == nil {
+		problems = []Problem{}
+	}
+
+	report := struct {
+		File     string    `json:"file"`
+		Problems []Problem `json:"problems"`
+	}{
+		File:     fileName,
+		Problems: problems,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
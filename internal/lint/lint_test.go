@@ -0,0 +1,124 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/chelnak/gh-changelog/internal/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintWithAValidChangelog(t *testing.T) {
+	t.Setenv("GH_REPO", "chelnak/gh-changelog")
+
+	problems, err := lint.Lint("CHANGELOG.md", "", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+	assert.False(t, lint.HasErrors(problems, true))
+
+	var buf bytes.Buffer
+	assert.NoError(t, lint.WriteText(&buf, "CHANGELOG.md", problems))
+	assert.Equal(t, "No problems found in CHANGELOG.md\n", buf.String())
+}
+
+func TestLintWithAPackageChangelog(t *testing.T) {
+	t.Setenv("GH_REPO", "chelnak/gh-changelog")
+
+	problems, err := lint.Lint("package_CHANGELOG.md", "", "", "api/")
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestLintWithFoldedPrereleases(t *testing.T) {
+	t.Setenv("GH_REPO", "chelnak/gh-changelog")
+
+	problems, err := lint.Lint("prerelease_CHANGELOG.md", "", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestLintReportsProblems(t *testing.T) {
+	t.Setenv("GH_REPO", "chelnak/gh-changelog")
+
+	problems, err := lint.Lint("invalid_CHANGELOG.md", "", "", "")
+	assert.NoError(t, err)
+
+	expected := []lint.Problem{
+		{Severity: lint.SeverityWarning, Message: "the changelog does not have an Unreleased section"},
+		{Severity: lint.SeverityError, Line: 6, Version: "v0.2.0", Message: "v0.2.0 is listed before v0.3.0 but is an older version"},
+		{Severity: lint.SeverityWarning, Line: 10, Version: "v0.2.0", Message: "Fixes is not a known section"},
+		{Severity: lint.SeverityError, Line: 14, Column: 68, Message: `invalid date for v0.3.0 "01/05/2022" (dates should be in the format YYYY-MM-DD)`},
+		{Severity: lint.SeverityWarning, Line: 18, Version: "v0.3.0", Message: "the Added section is empty"},
+		{Severity: lint.SeverityError, Line: 20, Version: "v0.1.0", Message: "v0.1.0 does not have a Full Changelog link that compares it with the previous version"},
+		{Severity: lint.SeverityError, Line: 28, Version: "v0.1.0", Message: "v0.1.0 is listed more than once (first on line 20)"},
+	}
+
+	assert.Equal(t, expected, problems)
+	assert.True(t, lint.HasErrors(problems, false))
+}
+
+func TestLintReportsCompareLinksToAnotherRepository(t *testing.T) {
+	problems, err := lint.Lint("CHANGELOG.md", "someone-else", "gh-changelog", "")
+	assert.NoError(t, err)
+
+	expected := []lint.Problem{
+		{Severity: lint.SeverityError, Line: 10, Version: "v0.2.0", Message: "the Full Changelog link of v0.2.0 is not in https://github.com/someone-else/gh-changelog"},
+		{Severity: lint.SeverityError, Line: 18, Version: "v0.1.0", Message: "the Full Changelog link of v0.1.0 is not in https://github.com/someone-else/gh-changelog"},
+	}
+
+	assert.Equal(t, expected, problems)
+}
+
+func TestHasErrorsWithWarnings(t *testing.T) {
+	problems := []lint.Problem{{Severity: lint.SeverityWarning, Message: "a warning"}}
+
+	assert.False(t, lint.HasErrors(problems, false))
+	assert.True(t, lint.HasErrors(problems, true))
+}
+
+func TestLintWithAMissingFile(t *testing.T) {
+	_, err := lint.Lint("missing.md", "", "", "")
+	assert.Error(t, err)
+}
+
+func TestWriteText(t *testing.T) {
+	problems := []lint.Problem{
+		{Severity: lint.SeverityWarning, Message: "the changelog does not have an Unreleased section"},
+		{Severity: lint.SeverityError, Line: 14, Column: 68, Message: "invalid date"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, lint.WriteText(&buf, "CHANGELOG.md", problems))
+
+	expected := `CHANGELOG.md: warning: the changelog does not have an Unreleased section
+CHANGELOG.md:14:68: error: invalid date
+
+Found 1 error(s) and 1 warning(s) in CHANGELOG.md
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	problems := []lint.Problem{
+		{Severity: lint.SeverityError, Line: 6, Version: "v0.2.0", Message: "v0.2.0 is listed before v0.3.0 but is an older version"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, lint.WriteJSON(&buf, "CHANGELOG.md", problems))
+
+	var report struct {
+		File     string                   `json:"file"`
+		Problems []map[string]interface{} `json:"problems"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, "CHANGELOG.md", report.File)
+	assert.Equal(t, []map[string]interface{}{
+		{"severity": "error", "line": float64(6), "version": "v0.2.0", "message": "v0.2.0 is listed before v0.3.0 but is an older version"},
+	}, report.Problems)
+
+	buf.Reset()
+	assert.NoError(t, lint.WriteJSON(&buf, "CHANGELOG.md", nil))
+	assert.JSONEq(t, `{"file": "CHANGELOG.md", "problems": []}`, buf.String())
+}
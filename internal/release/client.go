@@ -0,0 +1,103 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chelnak/gh-changelog/internal/utils"
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const releasesPerPage = 100
+
+// Release is a release of a GitHub repository.
+type Release struct {
+	ID         int64  `json:"id,omitempty"`
+	TagName    string `json:"tag_name"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	URL        string `json:"html_url,omitempty"`
+}
+
+// Client reads and writes the releases of a repository.
+type Client interface {
+	ListReleases() ([]Release, error)
+	CreateRelease(release Release) (Release, error)
+	UpdateRelease(release Release) (Release, error)
+}
+
+type restClient struct {
+	base    *api.RESTClient
+	baseURL string
+	owner   string
+	name    string
+}
+
+// NewClient returns a Client for the given repository. The baseURL is the root
+// of the REST API. When it is empty, the API of the repository host is used.
+func NewClient(repoContext utils.RepoContext, baseURL string) (Client, error) {
+	base, err := api.NewRESTClient(api.ClientOptions{Host: repoContext.Host})
+	if err != nil {
+		return nil, fmt.Errorf("could not create initial client: %s", err)
+	}
+
+	client := &restClient{
+		base:    base,
+		baseURL: baseURL,
+		owner:   repoContext.Owner,
+		name:    repoContext.Name,
+	}
+
+	return client, nil
+}
+
+func (client *restClient) path(format string, a ...interface{}) string {
+	return client.baseURL + fmt.Sprintf("repos/%s/%s/", client.owner, client.name) + fmt.Sprintf(format, a...)
+}
+
+// ListReleases returns every release of the repository, including drafts.
+func (client *restClient) ListReleases() ([]Release, error) {
+	var releases []Release
+	for page := 1; ; page++ {
+		var response []Release
+		err := client.base.Get(client.path("releases?per_page=%d&page=%d", releasesPerPage, page), &response)
+		if err != nil {
+			return nil, err
+		}
+
+		releases = append(releases, response...)
+
+		if len(response) < releasesPerPage {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+func (client *restClient) CreateRelease(release Release) (Release, error) {
+	body, err := json.Marshal(release)
+	if err != nil {
+		return Release{}, err
+	}
+
+	var response Release
+	err = client.base.Post(client.path("releases"), bytes.NewReader(body), &response)
+
+	return response, err
+}
+
+func (client *restClient) UpdateRelease(release Release) (Release, error) {
+	body, err := json.Marshal(release)
+	if err != nil {
+		return Release{}, err
+	}
+
+	var response Release
+	err = client.base.Patch(client.path("releases/%d", release.ID), bytes.NewReader(body), &response)
+
+	return response, err
+}
@@ -0,0 +1,175 @@
+// Package release creates and updates GitHub releases from the entries of a
+// changelog so that the release notes always match the changelog file.
+package release
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+)
+
+// Action describes what happened to the release of a tag.
+type Action string
+
+const (
+	ActionCreated   Action = "created"
+	ActionUpdated   Action = "updated"
+	ActionUnchanged Action = "unchanged"
+	ActionSkipped   Action = "skipped"
+)
+
+// Options control how a release is published. Draft and Prerelease are only
+// applied to an existing release when they are set, so that updating the notes
+// does not publish a draft or change the type of a release.
+type Options struct {
+	Draft      *bool
+	Prerelease *bool
+}
+
+// Result describes what happened to the release of a tag.
+type Result struct {
+	Tag    string
+	Action Action
+	URL    string
+}
+
+// Publish creates the release for the entry of the changelog, or updates it
+// if it already exists. The changelog is expected to hold a single entry, such
+// as the changelogs that are returned by get.GetLatest and get.GetVersion.
+func Publish(client Client, cl changelog.Changelog, opts Options) (Result, error) {
+	entries := cl.GetEntries()
+	if len(entries) == 0 {
+		return Result{}, errors.New("the changelog does not have an entry to release")
+	}
+
+	tag := entries[0].Tag
+
+	releases, err := getReleasesByTag(client)
+	if err != nil {
+		return Result{}, err
+	}
+
+	body, err := renderNotes(cl)
+	if err != nil {
+		return Result{}, err
+	}
+
+	release, ok := releases[tag]
+	if !ok {
+		created, err := client.CreateRelease(Release{
+			TagName:    tag,
+			Name:       tag,
+			Body:       body,
+			Draft:      opts.Draft != nil && *opts.Draft,
+			Prerelease: opts.Prerelease != nil && *opts.Prerelease,
+		})
+		if err != nil {
+			return Result{}, err
+		}
+
+		return Result{Tag: tag, Action: ActionCreated, URL: created.URL}, nil
+	}
+
+	updated := release
+	updated.Body = body
+	if opts.Draft != nil {
+		updated.Draft = *opts.Draft
+	}
+	if opts.Prerelease != nil {
+		updated.Prerelease = *opts.Prerelease
+	}
+
+	if sameBody(release.Body, body) && release.Draft == updated.Draft && release.Prerelease == updated.Prerelease {
+		return Result{Tag: tag, Action: ActionUnchanged, URL: release.URL}, nil
+	}
+
+	return updateRelease(client, updated)
+}
+
+// Sync updates the body of every existing release so that it matches the entry
+// for its tag. Releases are not created for entries that do not have one and
+// the draft and prerelease settings of existing releases are kept.
+func Sync(client Client, changelogs []changelog.Changelog) ([]Result, error) {
+	releases, err := getReleasesByTag(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, cl := range changelogs {
+		for _, e := range cl.GetEntries() {
+			release, ok := releases[e.Tag]
+			if !ok {
+				results = append(results, Result{Tag: e.Tag, Action: ActionSkipped})
+				continue
+			}
+
+			body, err := renderNotes(cl)
+			if err != nil {
+				return nil, err
+			}
+
+			if sameBody(release.Body, body) {
+				results = append(results, Result{Tag: e.Tag, Action: ActionUnchanged, URL: release.URL})
+				continue
+			}
+
+			release.Body = body
+
+			result, err := updateRelease(client, release)
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func updateRelease(client Client, release Release) (Result, error) {
+	updated, err := client.UpdateRelease(release)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Tag: release.TagName, Action: ActionUpdated, URL: updated.URL}, nil
+}
+
+func getReleasesByTag(client Client) (map[string]Release, error) {
+	releases, err := client.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	byTag := make(map[string]Release)
+	for _, release := range releases {
+		byTag[release.TagName] = release
+	}
+
+	return byTag, nil
+}
+
+// renderNotes renders the changelog in the same way as get --output notes.
+func renderNotes(cl changelog.Changelog) (string, error) {
+	var buf bytes.Buffer
+	if err := writer.Write(&buf, writer.TmplSrcNotes, cl); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// sameBody compares two release bodies. GitHub may return a body with Windows
+// line endings or surrounding whitespace, which does not count as a change.
+func sameBody(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+	}
+
+	return normalize(a) == normalize(b)
+}
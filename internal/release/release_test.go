@@ -0,0 +1,201 @@
+package release_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chelnak/gh-changelog/internal/get"
+	"github.com/chelnak/gh-changelog/internal/release"
+	"github.com/chelnak/gh-changelog/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const notes = `### Fixed
+
+- Cache the list of tags [#12](https://github.com/chelnak/gh-changelog/pull/12) ([chelnak](https://github.com/chelnak))`
+
+// stubServer is a minimal implementation of the releases API.
+type stubServer struct {
+	mu       sync.Mutex
+	releases []release.Release
+	requests []string
+}
+
+func (s *stubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+	const prefix = "/repos/chelnak/gh-changelog/releases"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == prefix:
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := min((page-1)*perPage, len(s.releases))
+		end := min(start+perPage, len(s.releases))
+		_ = json.NewEncoder(w).Encode(s.releases[start:end])
+	case r.Method == http.MethodPost && r.URL.Path == prefix:
+		var rel release.Release
+		_ = json.NewDecoder(r.Body).Decode(&rel)
+		rel.ID = int64(len(s.releases) + 1)
+		rel.URL = fmt.Sprintf("https://github.com/chelnak/gh-changelog/releases/tag/%s", rel.TagName)
+		s.releases = append(s.releases, rel)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(rel)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, prefix+"/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, prefix+"/"), 10, 64)
+		for i := range s.releases {
+			if s.releases[i].ID == id {
+				_ = json.NewDecoder(r.Body).Decode(&s.releases[i])
+				_ = json.NewEncoder(w).Encode(s.releases[i])
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *stubServer) find(tag string) *release.Release {
+	for i := range s.releases {
+		if s.releases[i].TagName == tag {
+			return &s.releases[i]
+		}
+	}
+
+	return nil
+}
+
+func setupClient(t *testing.T, releases ...release.Release) (release.Client, *stubServer) {
+	t.Setenv("GH_TOKEN", "test-token")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_REPO", "chelnak/gh-changelog")
+
+	stub := &stubServer{releases: releases}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	repoContext := utils.RepoContext{Owner: "chelnak", Name: "gh-changelog", Host: "github.com"}
+	client, err := release.NewClient(repoContext, server.URL+"/")
+	assert.NoError(t, err)
+
+	return client, stub
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestPublishCreatesARelease(t *testing.T) {
+	client, stub := setupClient(t)
+
+	cl, err := get.GetLatest("CHANGELOG.md", "", "")
+	assert.NoError(t, err)
+
+	result, err := release.Publish(client, cl, release.Options{Draft: boolPtr(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, release.Result{
+		Tag:    "v0.2.0",
+		Action: release.ActionCreated,
+		URL:    "https://github.com/chelnak/gh-changelog/releases/tag/v0.2.0",
+	}, result)
+
+	created := stub.find("v0.2.0")
+	assert.NotNil(t, created)
+	assert.Equal(t, "v0.2.0", created.Name)
+	assert.Equal(t, notes, created.Body)
+	assert.True(t, created.Draft)
+	assert.False(t, created.Prerelease)
+}
+
+func TestPublishUpdatesARelease(t *testing.T) {
+	client, stub := setupClient(t, release.Release{ID: 1, TagName: "v0.2.0", Name: "The second release", Body: "Old notes", Draft: true})
+
+	cl, err := get.GetVersion("CHANGELOG.md", "v0.2.0", "", "")
+	assert.NoError(t, err)
+
+	result, err := release.Publish(client, cl, release.Options{Prerelease: boolPtr(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, release.ActionUpdated, result.Action)
+
+	// The release is still a draft because --draft was not set.
+	updated := stub.find("v0.2.0")
+	assert.Equal(t, "The second release", updated.Name)
+	assert.Equal(t, notes, updated.Body)
+	assert.True(t, updated.Draft)
+	assert.True(t, updated.Prerelease)
+}
+
+func TestPublishKeepsTheTypeOfARelease(t *testing.T) {
+	client, stub := setupClient(t, release.Release{ID: 1, TagName: "v0.2.0", Name: "v0.2.0", Body: "Old notes", Prerelease: true})
+
+	cl, err := get.GetLatest("CHANGELOG.md", "", "")
+	assert.NoError(t, err)
+
+	result, err := release.Publish(client, cl, release.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, release.ActionUpdated, result.Action)
+
+	updated := stub.find("v0.2.0")
+	assert.Equal(t, notes, updated.Body)
+	assert.False(t, updated.Draft)
+	assert.True(t, updated.Prerelease)
+}
+
+func TestPublishPublishesADraft(t *testing.T) {
+	client, stub := setupClient(t, release.Release{ID: 1, TagName: "v0.2.0", Name: "v0.2.0", Body: notes, Draft: true})
+
+	cl, err := get.GetLatest("CHANGELOG.md", "", "")
+	assert.NoError(t, err)
+
+	result, err := release.Publish(client, cl, release.Options{Draft: boolPtr(false)})
+	assert.NoError(t, err)
+	assert.Equal(t, release.ActionUpdated, result.Action)
+	assert.False(t, stub.find("v0.2.0").Draft)
+}
+
+func TestPublishLeavesAnUnchangedRelease(t *testing.T) {
+	client, stub := setupClient(t, release.Release{ID: 1, TagName: "v0.2.0", Name: "v0.2.0", Body: strings.ReplaceAll(notes, "\n", "\r\n")})
+
+	cl, err := get.GetLatest("CHANGELOG.md", "", "")
+	assert.NoError(t, err)
+
+	result, err := release.Publish(client, cl, release.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, release.ActionUnchanged, result.Action)
+	assert.NotContains(t, stub.requests, "PATCH /repos/chelnak/gh-changelog/releases/1")
+}
+
+func TestSync(t *testing.T) {
+	// Enough releases to need a second page.
+	var releases []release.Release
+	for i := 1; i <= 120; i++ {
+		releases = append(releases, release.Release{ID: int64(i), TagName: fmt.Sprintf("other-%d", i)})
+	}
+	releases = append(releases, release.Release{ID: 121, TagName: "v0.1.0", Body: "Old notes", Prerelease: true})
+
+	client, stub := setupClient(t, releases...)
+
+	changelogs, err := get.GetEach("CHANGELOG.md", "", "")
+	assert.NoError(t, err)
+
+	results, err := release.Sync(client, changelogs)
+	assert.NoError(t, err)
+	assert.Equal(t, []release.Result{
+		{Tag: "v0.2.0", Action: release.ActionSkipped},
+		{Tag: "v0.1.0", Action: release.ActionUpdated},
+	}, results)
+
+	updated := stub.find("v0.1.0")
+	assert.Contains(t, updated.Body, "Initial release [#1]")
+	assert.True(t, updated.Prerelease)
+	assert.Nil(t, stub.find("v0.2.0"))
+}
@@ -0,0 +1,95 @@
+// Package update merges newly released entries in to an existing changelog.
+// Entries that are already in the changelog are kept exactly as they were
+// found so that any changes made to them by hand are not lost.
+package update
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/parser"
+)
+
+// Read parses the existing changelog at the given path. When repoOwner and
+// repoName are empty, the repository in the current directory is used.
+func Read(fileName, repoOwner, repoName string) (changelog.Changelog, error) {
+	if _, err := os.Stat(fileName); err != nil {
+		return nil, fmt.Errorf("could not read %s. Run 'gh changelog new' to create a changelog first", fileName)
+	}
+
+	parser := parser.NewParser(fileName, repoOwner, repoName)
+	return parser.Parse()
+}
+
+// GetLatestVersion returns the tag of the latest entry in the changelog. An
+// empty string is returned if the changelog does not have any entries.
+func GetLatestVersion(changelog changelog.Changelog) string {
+	latest := changelog.Tail()
+	if latest == nil {
+		return ""
+	}
+
+	return latest.Tag
+}
+
+// Write renders the given changelog with tmplSrc and writes it to w.
+// The changelog should contain only the entries that are newer than tag. The
+// released entries of the existing changelog, starting with the entry for
+// tag, are then written after it unchanged. If tag is empty, only the given
+// changelog is written.
+func Write(w io.Writer, tmplSrc string, changelog changelog.Changelog, existing []byte, tag string) error {
+	var buf bytes.Buffer
+	if err := writer.Write(&buf, tmplSrc, changelog); err != nil {
+		return err
+	}
+
+	if tag == "" {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	offset := findEntry(existing, tag)
+	if offset < 0 {
+		return fmt.Errorf("could not find the entry for %s in the existing changelog", tag)
+	}
+
+	_, err := fmt.Fprintf(w, "%s\n\n%s", bytes.TrimRight(buf.Bytes(), "\n"), existing[offset:])
+	return err
+}
+
+// WriteFile updates the changelog at fileName. See Write for details.
+func WriteFile(fileName, tmplSrc string, changelog changelog.Changelog, tag string) error {
+	existing, err := os.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tmplSrc, changelog, existing, tag); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Clean(fileName), buf.Bytes(), 0600)
+}
+
+// findEntry returns the offset of the heading of the entry for the given tag,
+// or -1 if it can not be found. Both linked (## [v1.0.0](...)) and plain
+// (## v1.0.0) headings are recognised.
+func findEntry(content []byte, tag string) int {
+	offset := 0
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		heading := strings.TrimSpace(line)
+		if strings.HasPrefix(heading, "## ["+tag+"]") || heading == "## "+tag || strings.HasPrefix(heading, "## "+tag+" ") {
+			return offset
+		}
+		offset += len(line)
+	}
+
+	return -1
+}
@@ -0,0 +1,137 @@
+package update_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/update"
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"github.com/stretchr/testify/assert"
+)
+
+var fileName string = "CHANGELOG.md"
+
+const (
+	repoName  = "gh-changelog"
+	repoOwner = "chelnak"
+)
+
+func released(t *testing.T) string {
+	content, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+
+	released := string(content)
+	return released[strings.Index(released, "## [v0.15.1]"):]
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	t.Setenv("GH_REPO", "chelnak/gh-changelog")
+
+	existing, err := update.Read(fileName, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.15.1", update.GetLatestVersion(existing))
+
+	_, err = update.Read("missing.md", "", "")
+	assert.ErrorContains(t, err, "gh changelog new")
+
+	assert.Equal(t, "", update.GetLatestVersion(changelog.NewChangelog(repoOwner, repoName)))
+}
+
+func TestWriteKeepsThePreamble(t *testing.T) {
+	existing, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+
+	cl := changelog.NewChangelog(repoOwner, repoName)
+	cl.SetPreamble("# Release history")
+
+	var buf bytes.Buffer
+	err = update.Write(&buf, writer.TmplSrcStandard, cl, existing, "v0.15.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "# Release history\n\n"+released(t), buf.String())
+}
+
+func TestWriteKeepsTheUnreleasedDescriptionUnderItsHeading(t *testing.T) {
+	existing, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+
+	cl := changelog.NewChangelog(repoOwner, repoName)
+	cl.SetPreamble("# Release history")
+	cl.SetUnreleasedDescription("These changes will be in the next release.")
+	cl.AddUnreleased([]entry.Item{{Text: "Unreleased 1"}})
+
+	var buf bytes.Buffer
+	err = update.Write(&buf, writer.TmplSrcStandard, cl, existing, "v0.15.1")
+	assert.NoError(t, err)
+
+	expected := "# Release history\n\n## Unreleased\n\nThese changes will be in the next release.\n\n- Unreleased 1\n\n"
+	assert.Equal(t, expected+released(t), buf.String())
+}
+
+func TestWriteAddsNewEntriesAndKeepsExistingOnes(t *testing.T) {
+	existing, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+
+	cl := changelog.NewChangelog(repoOwner, repoName)
+	e := entry.NewEntry("v0.16.0", time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC))
+	e.PrevTag = "v0.15.1"
+	assert.NoError(t, e.Append("Added", entry.Item{Title: "A new feature", Number: 150, Author: "test-user"}))
+	cl.Insert(e)
+	cl.AddUnreleased([]entry.Item{{Title: "Not released yet", Number: 151, Author: "test-user"}})
+
+	var buf bytes.Buffer
+	err = update.Write(&buf, writer.TmplSrcStandard, cl, existing, "v0.15.1")
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "## Unreleased\n\n- Not released yet [#151]")
+	assert.Contains(t, output, "## [v0.16.0](https://github.com/chelnak/gh-changelog/tree/v0.16.0) - 2023-11-01")
+	assert.Contains(t, output, "[Full Changelog](https://github.com/chelnak/gh-changelog/compare/v0.15.1...v0.16.0)")
+	assert.Contains(t, output, "- A new feature [#150](https://github.com/chelnak/gh-changelog/pull/150) ([test-user](https://github.com/test-user))\n\n## [v0.15.1]")
+	assert.True(t, strings.HasSuffix(output, released(t)))
+	assert.Contains(t, output, "This release was edited by hand.")
+}
+
+func TestWriteWithoutNewEntries(t *testing.T) {
+	existing, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+
+	cl := changelog.NewChangelog(repoOwner, repoName)
+
+	var buf bytes.Buffer
+	err = update.Write(&buf, writer.TmplSrcStandard, cl, existing, "v0.15.1")
+	assert.NoError(t, err)
+	assert.Equal(t, string(existing), buf.String())
+}
+
+func TestWriteErrorsWhenTheEntryIsMissing(t *testing.T) {
+	cl := changelog.NewChangelog(repoOwner, repoName)
+
+	var buf bytes.Buffer
+	err := update.Write(&buf, writer.TmplSrcStandard, cl, []byte("# Changelog\n"), "v1.0.0")
+	assert.ErrorContains(t, err, "could not find the entry for v1.0.0")
+}
+
+func TestWriteFile(t *testing.T) {
+	existing, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	assert.NoError(t, os.WriteFile(path, existing, 0600))
+
+	cl := changelog.NewChangelog(repoOwner, repoName)
+	cl.Insert(entry.NewEntry("v0.16.0", time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)))
+
+	err = update.WriteFile(path, writer.TmplSrcStandard, cl, "v0.15.1")
+	assert.NoError(t, err)
+
+	updated, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(updated), "## [v0.16.0]")
+	assert.True(t, strings.HasSuffix(string(updated), released(t)))
+}
@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/chelnak/gh-changelog/internal/version"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/fatih/color"
 )
@@ -89,6 +92,33 @@ func NextVersionIsGreaterThanCurrent(nextVersion, currentVersion string) bool {
 	return nextSemVer.GreaterThan(currentSemVer)
 }
 
+// IncrementVersion increments the major, minor or patch segment of the given
+// version. A leading v is preserved.
+func IncrementVersion(currentVersion, increment string) (string, error) {
+	current, err := version.NormalizeVersion(currentVersion)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid semantic version", currentVersion)
+	}
+
+	var next semver.Version
+	switch increment {
+	case "major":
+		next = current.IncMajor()
+	case "minor":
+		next = current.IncMinor()
+	case "patch":
+		next = current.IncPatch()
+	default:
+		return "", fmt.Errorf("'%s' is not a valid increment. Valid values are 'major', 'minor' and 'patch'", increment)
+	}
+
+	if strings.HasPrefix(currentVersion, "v") {
+		return fmt.Sprintf("v%s", next.String()), nil
+	}
+
+	return next.String(), nil
+}
+
 func parseLocalVersion(version string) string {
 	slice := strings.Split(version, " ")
 
@@ -99,13 +129,16 @@ func parseLocalVersion(version string) string {
 	return slice[2]
 }
 
-// RepoContext is a struct that contains the current repository owner and name.
+// RepoContext is a struct that contains the current repository owner, name
+// and the host that it lives on.
 type RepoContext struct {
 	Owner string
 	Name  string
+	Host  string
 }
 
-// GetRepoContext returns a new RepoContext struct with the current repository owner and name.
+// GetRepoContext returns a new RepoContext struct with the current repository owner, name and host.
+// The host is taken from the git remote, or from GH_HOST or GH_REPO when they are set.
 func GetRepoContext() (RepoContext, error) {
 	currentRepository, err := repository.Current()
 	if err != nil {
@@ -119,5 +152,75 @@ func GetRepoContext() (RepoContext, error) {
 	return RepoContext{
 		Owner: currentRepository.Owner,
 		Name:  currentRepository.Name,
+		Host:  currentRepository.Host,
 	}, nil
 }
+
+// DefaultHost returns the host that the GitHub CLI uses when a repository does
+// not specify one. It can be changed with GH_HOST.
+func DefaultHost() string {
+	host, _ := auth.DefaultHost()
+	return host
+}
+
+// ParseRepoContext returns a new RepoContext struct for a repository in the
+// [HOST/]OWNER/NAME format. When the host is omitted, the default host of the
+// GitHub CLI is used.
+func ParseRepoContext(repo string) (RepoContext, error) {
+	r, err := repository.Parse(repo)
+	if err != nil {
+		return RepoContext{}, fmt.Errorf("'%s' is not a valid repository. The expected format is [HOST/]OWNER/NAME", repo)
+	}
+
+	return RepoContext{
+		Owner: r.Owner,
+		Name:  r.Name,
+		Host:  r.Host,
+	}, nil
+}
+
+// MatchPath reports whether the file at the given path matches the glob
+// pattern. * and ? do not match a path separator and ** matches any number of
+// directories. A pattern without any wildcards matches the path itself and
+// everything below it.
+func MatchPath(pattern, name string) bool {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.ContainsAny(pattern, "*?") {
+		return name == pattern || strings.HasPrefix(name, pattern+"/")
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				// **/ also matches no directories at all.
+				if i+2 < len(pattern) && pattern[i+2] == '/' {
+					expr.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					expr.WriteString(".*")
+					i++
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	matched, err := regexp.MatchString(expr.String(), name)
+	return err == nil && matched
+}
+
+// MatchLogin reports whether a GitHub login matches the pattern. Logins are
+// compared case-insensitively and * matches any number of characters, so
+// *[bot] matches the login of every bot.
+func MatchLogin(pattern, login string) bool {
+	return MatchPath(strings.ToLower(pattern), strings.ToLower(login))
+}
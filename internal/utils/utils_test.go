@@ -115,6 +115,47 @@ func TestVersionIsGreaterThan(t *testing.T) {
 	}
 }
 
+func TestIncrementVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		increment string
+		want      string
+	}{
+		{
+			name:      "major increment",
+			current:   "v1.2.3",
+			increment: "major",
+			want:      "v2.0.0",
+		},
+		{
+			name:      "minor increment",
+			current:   "v1.2.3",
+			increment: "minor",
+			want:      "v1.3.0",
+		},
+		{
+			name:      "patch increment without a leading v",
+			current:   "1.2.3",
+			increment: "patch",
+			want:      "1.2.4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utils.IncrementVersion(tt.current, tt.increment)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIncrementVersionWithAnInvalidIncrement(t *testing.T) {
+	_, err := utils.IncrementVersion("v1.2.3", "huge")
+	assert.Error(t, err)
+}
+
 func TestVersionIsGreaterThanPreRelease(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -225,3 +266,71 @@ func TestCheckForUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRepoContext(t *testing.T) {
+	t.Setenv("GH_HOST", "")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	repo, err := utils.ParseRepoContext("chelnak/gh-changelog")
+	assert.NoError(t, err)
+	assert.Equal(t, utils.RepoContext{Owner: "chelnak", Name: "gh-changelog", Host: "github.com"}, repo)
+
+	repo, err = utils.ParseRepoContext("ghes.example.com/chelnak/gh-changelog")
+	assert.NoError(t, err)
+	assert.Equal(t, utils.RepoContext{Owner: "chelnak", Name: "gh-changelog", Host: "ghes.example.com"}, repo)
+
+	t.Setenv("GH_HOST", "ghes.example.com")
+	repo, err = utils.ParseRepoContext("chelnak/gh-changelog")
+	assert.NoError(t, err)
+	assert.Equal(t, "ghes.example.com", repo.Host)
+
+	_, err = utils.ParseRepoContext("gh-changelog")
+	assert.EqualError(t, err, "'gh-changelog' is not a valid repository. The expected format is [HOST/]OWNER/NAME")
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"services/api", "services/api/main.go", true},
+		{"services/api/", "services/api/handlers/users.go", true},
+		{"services/api", "services/api-gateway/main.go", false},
+		{"services/api/**", "services/api/handlers/users.go", true},
+		{"services/api/**", "services/web/main.go", false},
+		{"services/*/go.mod", "services/api/go.mod", true},
+		{"services/*/go.mod", "services/api/internal/go.mod", false},
+		{"**/*.proto", "proto/api/v1/users.proto", true},
+		{"**/*.proto", "users.proto", true},
+		{"docs/?.md", "docs/a.md", true},
+		{"docs/?.md", "docs/ab.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.MatchPath(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestMatchLogin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		login   string
+		want    bool
+	}{
+		{"*[bot]", "dependabot[bot]", true},
+		{"*[bot]", "renovate[bot]", true},
+		{"*[bot]", "robot", false},
+		{"renovate*", "Renovate-Bot", true},
+		{"octocat", "OctoCat", true},
+		{"octocat", "octocat2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.login, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.MatchLogin(tt.pattern, tt.login))
+		})
+	}
+}
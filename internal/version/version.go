@@ -97,6 +97,22 @@ func NormalizeVersion(v string) (*semver.Version, error) {
 	return semver.NewVersion(sv.String())
 }
 
+// IsPrereleaseOf returns true if v is a pre-release of the stable version, for
+// example v2.0.0-rc.1 is a pre-release of v2.0.0.
+func IsPrereleaseOf(v, stable string) bool {
+	pre, err := NormalizeVersion(v)
+	if err != nil || pre.Prerelease() == "" {
+		return false
+	}
+
+	release, err := NormalizeVersion(stable)
+	if err != nil || release.Prerelease() != "" {
+		return false
+	}
+
+	return pre.Major() == release.Major() && pre.Minor() == release.Minor() && pre.Patch() == release.Patch()
+}
+
 // String converts a Version object to a string.
 // Note, if the original version contained a leading v this version will not.
 // See the Original() method to retrieve the original value. Semantic Versions
@@ -0,0 +1,111 @@
+package writer
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdown converts the text of a changelog that was read from a file. Raw
+// HTML in the text is escaped, in the same way as the titles of pull
+// requests.
+var markdown = goldmark.New(
+	goldmark.WithRendererOptions(
+		renderer.WithNodeRenderers(util.Prioritized(escapedHTMLRenderer{}, 100)),
+	),
+)
+
+// escapedHTMLRenderer renders raw HTML as escaped text.
+type escapedHTMLRenderer struct{}
+
+func (r escapedHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+}
+
+func (r escapedHTMLRenderer) renderRawHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		segments := node.(*ast.RawHTML).Segments
+		for i := 0; i < segments.Len(); i++ {
+			segment := segments.At(i)
+			_, _ = w.Write(util.EscapeHTML(segment.Value(source)))
+		}
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r escapedHTMLRenderer) renderHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.HTMLBlock)
+	if entering {
+		_, _ = w.WriteString("<p>")
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			_, _ = w.Write(util.EscapeHTML(line.Value(source)))
+		}
+	} else {
+		if n.HasClosure() {
+			_, _ = w.Write(util.EscapeHTML(n.ClosureLine.Value(source)))
+		}
+		_, _ = w.WriteString("</p>\n")
+	}
+
+	return ast.WalkContinue, nil
+}
+
+// markdownToHTML converts markdown to HTML.
+func markdownToHTML(src string) string {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(src), &buf); err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// markdownToInlineHTML converts markdown to HTML that can be used inline, such
+// as in a list item. Text that is a single paragraph is returned without the
+// paragraph element.
+func markdownToInlineHTML(src string) string {
+	out := markdownToHTML(src)
+	if strings.Count(out, "<p>") == 1 && strings.HasPrefix(out, "<p>") && strings.HasSuffix(out, "</p>") {
+		return strings.TrimSuffix(strings.TrimPrefix(out, "<p>"), "</p>")
+	}
+
+	return out
+}
+
+// # Changelog or <!-- markdownlint-disable MD024 -->
+var preambleTitleRegex = regexp.MustCompile(`^(# .*|<!--.*-->)$`)
+
+// preambleText returns the preamble of a changelog without its title and
+// comments, which are only needed in the markdown file. Other formats write
+// a title of their own.
+func preambleText(preamble string) string {
+	var lines []string
+	for _, line := range strings.Split(preamble, "\n") {
+		if !preambleTitleRegex.MatchString(strings.TrimSpace(line)) {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// nestedText returns the lines after the first line of an item that was read
+// from a changelog, such as a nested list or a code block. It is empty when
+// the item has a single line.
+func nestedText(item entry.Item) string {
+	_, rest, found := strings.Cut(item.Text, "\n")
+	if !found {
+		return ""
+	}
+
+	return "\n" + rest
+}
@@ -0,0 +1,492 @@
+package writer
+
+import (
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatAsciiDoc = "asciidoc"
+	FormatRST      = "rst"
+	FormatText     = "text"
+)
+
+// Formats are the formats that a changelog can be rendered in.
+var Formats = []string{FormatMarkdown, FormatHTML, FormatAsciiDoc, FormatRST, FormatText}
+
+// extensions are the file extensions of the formats other than markdown.
+var extensions = map[string]string{
+	FormatHTML:     ".html",
+	FormatAsciiDoc: ".adoc",
+	FormatRST:      ".rst",
+	FormatText:     ".txt",
+}
+
+// FileName returns the name of the file that a changelog in the given format
+// is written to. The extension of fileName is replaced for formats other than
+// markdown, so that CHANGELOG.md is never overwritten with another format.
+func FileName(fileName, format string) string {
+	ext, ok := extensions[format]
+	if !ok {
+		return fileName
+	}
+
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ext
+}
+
+// Renderer renders a changelog in a single format.
+type Renderer func(writer io.Writer, changelog changelog.Changelog) error
+
+var renderers = map[string]Renderer{
+	FormatHTML:     renderHTML,
+	FormatAsciiDoc: renderTemplate(tmplAsciiDoc, formatAsciiDocItem, formatAsciiDocContributor),
+	FormatRST:      renderTemplate(tmplRST, formatRSTItem, formatRSTContributor),
+	FormatText:     renderTemplate(tmplText, formatTextItem, formatTextContributor),
+}
+
+const tmplHTML = `<h1>Changelog</h1>
+{{- with preamble .GetPreamble }}
+{{ markdown . }}
+{{- end }}
+{{- $unreleased := .GetUnreleased }}
+{{- if or $unreleased .GetUnreleasedDescription }}
+
+<h2 id="unreleased">Unreleased</h2>
+{{- with .GetUnreleasedDescription }}
+{{ markdown . }}
+{{- end }}
+{{- with $unreleased }}
+<ul>
+{{- range . }}
+<li>{{ formatItem . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+{{- range .GetEntries }}
+
+<h2 id="{{ anchor .Tag }}"><a href="{{ tagURL .Tag }}">{{ .Tag }}</a> - {{ formatDate .Date }}</h2>{{ template "entry" . }}
+{{- end }}
+{{- define "entry" }}
+<p><a href="{{ compareURL (previousTag .) .Tag }}">Full Changelog</a></p>
+{{- with .Description }}
+{{ markdown . }}
+{{- end }}
+{{- range .Sections }}
+{{- if or .Items .Description }}
+<h3>{{ .Name }}</h3>
+{{- with .Description }}
+{{ markdown . }}
+{{- end }}
+{{- with .Items }}
+<ul>
+{{- range . }}
+<li>{{ formatItem . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Contributors }}
+<h3>Contributors</h3>
+<ul>
+{{- range . }}
+<li>{{ formatContributor . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+`
+
+const tmplAsciiDoc = `= Changelog
+{{- with preamble .GetPreamble }}
+
+{{ . }}
+{{- end }}
+{{- $unreleased := .GetUnreleased }}
+{{- if or $unreleased .GetUnreleasedDescription }}
+
+== Unreleased
+{{- with .GetUnreleasedDescription }}
+
+{{ . }}
+{{- end }}
+{{- with $unreleased }}
+{{ range . }}
+* {{ formatItem . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- range .GetEntries }}
+
+[#{{ anchor .Tag }}]
+== {{ tagURL .Tag }}[{{ .Tag }}] - {{ formatDate .Date }}
+
+{{ compareURL (previousTag .) .Tag }}[Full Changelog]
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- range .Sections }}
+{{- if or .Items .Description }}
+
+=== {{ .Name }}
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- with .Items }}
+{{ range . }}
+* {{ formatItem . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Contributors }}
+
+=== Contributors
+{{ range . }}
+* {{ formatContributor . }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
+const tmplRST = `Changelog
+=========
+{{- with preamble .GetPreamble }}
+
+{{ . }}
+{{- end }}
+{{- $unreleased := .GetUnreleased }}
+{{- if or $unreleased .GetUnreleasedDescription }}
+
+Unreleased
+----------
+{{- with .GetUnreleasedDescription }}
+
+{{ . }}
+{{- end }}
+{{- with $unreleased }}
+{{ range . }}
+- {{ formatItem . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- range .GetEntries }}
+{{ $heading := printf "%s - %s" .Tag (formatDate .Date) }}
+.. _{{ anchor .Tag }}:
+
+{{ $heading }}
+{{ underline "-" $heading }}
+
+` + "`" + `Full Changelog <{{ compareURL (previousTag .) .Tag }}>` + "`" + `__
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- range .Sections }}
+{{- if or .Items .Description }}
+
+{{ .Name }}
+{{ underline "~" .Name }}
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- with .Items }}
+{{ range . }}
+- {{ formatItem . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Contributors }}
+
+Contributors
+~~~~~~~~~~~~
+{{ range . }}
+- {{ formatContributor . }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
+const tmplText = `Changelog
+{{- with preamble .GetPreamble }}
+
+{{ . }}
+{{- end }}
+{{- $unreleased := .GetUnreleased }}
+{{- if or $unreleased .GetUnreleasedDescription }}
+
+Unreleased
+{{- with .GetUnreleasedDescription }}
+
+{{ . }}
+{{- end }}
+{{- with $unreleased }}
+{{ range . }}
+  - {{ formatItem . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- range .GetEntries }}
+
+{{ .Tag }} - {{ formatDate .Date }}
+Full Changelog: {{ compareURL (previousTag .) .Tag }}
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- range .Sections }}
+{{- if or .Items .Description }}
+
+{{ .Name }}
+{{- with .Description }}
+{{ . }}
+{{- end }}
+{{- range .Items }}
+  - {{ formatItem . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Contributors }}
+
+Contributors
+{{- range . }}
+  - {{ formatContributor . }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
+// ValidateFormat returns an error if format is not one of Formats. An empty
+// format is rendered as markdown.
+func ValidateFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("'%s' is not a valid format. Valid values are %s", format, strings.Join(Formats, ", "))
+}
+
+// Render writes the changelog to writer in the given format. Markdown is
+// rendered with tmplSrc so that custom templates can be used. The other
+// formats are rendered with built-in templates that provide the same content.
+func Render(writer io.Writer, format, tmplSrc string, changelog changelog.Changelog) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+
+	if format == "" || format == FormatMarkdown {
+		return Write(writer, tmplSrc, changelog)
+	}
+
+	return renderers[format](writer, changelog)
+}
+
+var anchorRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// anchor returns an identifier for a tag that can be linked to, for example
+// v1-2-0 for v1.2.0.
+func anchor(tag string) string {
+	return strings.Trim(anchorRegex.ReplaceAllString(strings.ToLower(tag), "-"), "-")
+}
+
+// previousTag returns the tag or commit that an entry is compared with.
+func previousTag(e *entry.Entry) string {
+	switch {
+	case e.PrevTag != "":
+		return e.PrevTag
+	case e.Previous != nil:
+		return e.Previous.Tag
+	default:
+		return getFirstCommit()
+	}
+}
+
+func underline(char, text string) string {
+	return strings.Repeat(char, len(text))
+}
+
+// renderTemplate returns a renderer that executes a text template. Items and
+// contributors are formatted with formatItem and formatContributor.
+func renderTemplate(
+	tmplSrc string,
+	formatItem func(l links, item entry.Item) string,
+	formatContributor func(l links, c entry.Contributor) string,
+) Renderer {
+	return func(writer io.Writer, changelog changelog.Changelog) error {
+		l := links{changelog: changelog}
+
+		funcs := funcMap(changelog)
+		funcs["anchor"] = anchor
+		funcs["previousTag"] = previousTag
+		funcs["underline"] = underline
+		funcs["preamble"] = preambleText
+		funcs["formatItem"] = func(item entry.Item) string {
+			return formatItem(l, item)
+		}
+		funcs["formatContributor"] = func(c entry.Contributor) string {
+			return formatContributor(l, c)
+		}
+
+		tmpl, err := template.New("changelog").Funcs(funcs).Parse(tmplSrc)
+		if err != nil {
+			return err
+		}
+
+		return tmpl.Execute(writer, changelog)
+	}
+}
+
+// WriteEntryHTML writes the body of an entry as HTML, without the heading
+// that holds its tag and date. It is used where the tag and date are shown
+// separately, such as the items of a feed.
+func WriteEntryHTML(writer io.Writer, changelog changelog.Changelog, e *entry.Entry) error {
+	tmpl, err := parseHTML(changelog)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.ExecuteTemplate(writer, "entry", e)
+}
+
+func renderHTML(writer io.Writer, changelog changelog.Changelog) error {
+	tmpl, err := parseHTML(changelog)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(writer, changelog)
+}
+
+func parseHTML(changelog changelog.Changelog) (*htmltemplate.Template, error) {
+	l := links{changelog: changelog}
+
+	funcs := htmltemplate.FuncMap(funcMap(changelog))
+	funcs["anchor"] = anchor
+	funcs["previousTag"] = previousTag
+	funcs["preamble"] = preambleText
+	funcs["markdown"] = func(src string) htmltemplate.HTML {
+		return htmltemplate.HTML(markdownToHTML(src)) // #nosec G203 -- raw HTML is escaped
+	}
+	funcs["formatItem"] = func(item entry.Item) htmltemplate.HTML {
+		return htmltemplate.HTML(formatHTMLItem(l, item)) // #nosec G203 -- every part is escaped
+	}
+	funcs["formatContributor"] = func(c entry.Contributor) htmltemplate.HTML {
+		return htmltemplate.HTML(formatHTMLContributor(l, c)) // #nosec G203 -- every part is escaped
+	}
+
+	return htmltemplate.New("changelog").Funcs(funcs).Parse(tmplHTML)
+}
+
+// formatHTMLItem formats an item with escaped text. Items that were read from
+// an existing changelog are converted from their original markdown text when
+// they could not be matched to a pull request or hold more than its line.
+func formatHTMLItem(l links, item entry.Item) string {
+	if item.Text != "" && (item.Number == 0 || nestedText(item) != "") {
+		return markdownToInlineHTML(item.Text)
+	}
+
+	if isCommit(item) {
+		return fmt.Sprintf(
+			`%s <a href="%s">%s</a>`,
+			html.EscapeString(item.Title),
+			html.EscapeString(l.commitURL(item.MergeSha)),
+			shortSha(item.MergeSha),
+		)
+	}
+
+	if item.Number == 0 {
+		return html.EscapeString(item.Text)
+	}
+
+	return fmt.Sprintf(
+		`%s <a href="%s">#%d</a> (<a href="%s">%s</a>)`,
+		html.EscapeString(item.Title),
+		html.EscapeString(l.itemURL(item)),
+		item.Number,
+		html.EscapeString(l.userURL(item.Author)),
+		html.EscapeString(item.Author),
+	)
+}
+
+func formatAsciiDocItem(l links, item entry.Item) string {
+	if isCommit(item) {
+		return fmt.Sprintf("%s %s[%s]", item.Title, l.commitURL(item.MergeSha), shortSha(item.MergeSha))
+	}
+
+	if item.Number == 0 {
+		return item.Text
+	}
+
+	return fmt.Sprintf("%s %s[#%d] (%s[%s])", item.Title, l.itemURL(item), item.Number, l.userURL(item.Author), item.Author) + nestedText(item)
+}
+
+func formatRSTItem(l links, item entry.Item) string {
+	if isCommit(item) {
+		return fmt.Sprintf("%s `%s <%s>`__", item.Title, shortSha(item.MergeSha), l.commitURL(item.MergeSha))
+	}
+
+	if item.Number == 0 {
+		return item.Text
+	}
+
+	return fmt.Sprintf("%s `#%d <%s>`__ (`%s <%s>`__)", item.Title, item.Number, l.itemURL(item), item.Author, l.userURL(item.Author)) + nestedText(item)
+}
+
+func formatTextItem(_ links, item entry.Item) string {
+	if isCommit(item) {
+		return fmt.Sprintf("%s %s", item.Title, shortSha(item.MergeSha))
+	}
+
+	text := item.Text
+	if item.Number != 0 {
+		text = fmt.Sprintf("%s #%d (%s)", item.Title, item.Number, item.Author) + nestedText(item)
+	}
+
+	// Nested lines are indented so that they stay under the item.
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = "  " + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatHTMLContributor(l links, c entry.Contributor) string {
+	return fmt.Sprintf(`<a href="%s">@%s</a>%s`, html.EscapeString(l.userURL(c.Login)), html.EscapeString(c.Login), firstContribution(c))
+}
+
+func formatAsciiDocContributor(l links, c entry.Contributor) string {
+	return fmt.Sprintf("%s[@%s]%s", l.userURL(c.Login), c.Login, firstContribution(c))
+}
+
+func formatRSTContributor(l links, c entry.Contributor) string {
+	return fmt.Sprintf("`@%s <%s>`__%s", c.Login, l.userURL(c.Login), firstContribution(c))
+}
+
+func formatTextContributor(_ links, c entry.Contributor) string {
+	return "@" + c.Login + firstContribution(c)
+}
@@ -0,0 +1,172 @@
+package writer_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"github.com/chelnak/gh-changelog/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRenderChangelog() changelog.Changelog {
+	cl := changelog.NewChangelog(repoOwner, repoName)
+
+	one := entry.Entry{
+		Tag:  "v1.0.0",
+		Date: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Sections: []entry.Section{
+			{
+				Name: "Added",
+				Items: []entry.Item{
+					{Title: "Add <b>bold</b> feature", Number: 12, Author: "octocat"},
+					{Text: "A change that was written by hand"},
+				},
+			},
+		},
+	}
+
+	two := entry.Entry{Tag: "v0.9.0", Date: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	one.Previous = &two
+
+	cl.Insert(one)
+	cl.AddUnreleased([]entry.Item{{Text: "Unreleased 1"}})
+
+	return cl
+}
+
+func Test_RenderWritesMarkdownWithTheTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.Render(&buf, writer.FormatMarkdown, writer.TmplSrcStandard, setupRenderChangelog())
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "## [v1.0.0](https://github.com/repo-owner/repo-name/tree/v1.0.0) - 2023-01-02")
+}
+
+func Test_RenderWritesHTMLWithAnchorsPerVersion(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.Render(&buf, writer.FormatHTML, "", setupRenderChangelog())
+
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `<h2 id="unreleased">Unreleased</h2>`)
+	assert.Contains(t, out, `<h2 id="v1-0-0"><a href="https://github.com/repo-owner/repo-name/tree/v1.0.0">v1.0.0</a> - 2023-01-02</h2>`)
+	assert.Contains(t, out, `<a href="https://github.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0">Full Changelog</a>`)
+	assert.Contains(t, out, "<h3>Added</h3>")
+	assert.Contains(t, out, `<li>Add &lt;b&gt;bold&lt;/b&gt; feature <a href="https://github.com/repo-owner/repo-name/pull/12">#12</a> (<a href="https://github.com/octocat">octocat</a>)</li>`)
+	assert.Contains(t, out, "<li>A change that was written by hand</li>")
+}
+
+func Test_RenderWritesAsciiDoc(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.Render(&buf, writer.FormatAsciiDoc, "", setupRenderChangelog())
+
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "= Changelog\n\n== Unreleased\n\n* Unreleased 1\n")
+	assert.Contains(t, out, "[#v1-0-0]\n== https://github.com/repo-owner/repo-name/tree/v1.0.0[v1.0.0] - 2023-01-02\n")
+	assert.Contains(t, out, "https://github.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0[Full Changelog]")
+	assert.Contains(t, out, "=== Added\n\n* Add <b>bold</b> feature https://github.com/repo-owner/repo-name/pull/12[#12] (https://github.com/octocat[octocat])\n")
+}
+
+func Test_RenderWritesRST(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.Render(&buf, writer.FormatRST, "", setupRenderChangelog())
+
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "Changelog\n=========\n\nUnreleased\n----------\n\n- Unreleased 1\n")
+	assert.Contains(t, out, ".. _v1-0-0:\n\nv1.0.0 - 2023-01-02\n-------------------\n")
+	assert.Contains(t, out, "`Full Changelog <https://github.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0>`__")
+	assert.Contains(t, out, "Added\n~~~~~\n\n- Add <b>bold</b> feature `#12 <https://github.com/repo-owner/repo-name/pull/12>`__ (`octocat <https://github.com/octocat>`__)\n")
+}
+
+func Test_RenderWritesPlainText(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.Render(&buf, writer.FormatText, "", setupRenderChangelog())
+
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "Unreleased\n\n  - Unreleased 1\n")
+	assert.Contains(t, out, "v1.0.0 - 2023-01-02\nFull Changelog: https://github.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0\n")
+	assert.Contains(t, out, "Added\n  - Add <b>bold</b> feature #12 (octocat)\n  - A change that was written by hand\n")
+}
+
+func Test_RenderReturnsAnErrorForAnUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.Render(&buf, "pdf", "", setupRenderChangelog())
+
+	assert.EqualError(t, err, "'pdf' is not a valid format. Valid values are markdown, html, asciidoc, rst, text")
+	assert.Empty(t, buf.String())
+}
+
+func Test_ValidateFormat(t *testing.T) {
+	for _, format := range append([]string{""}, writer.Formats...) {
+		assert.NoError(t, writer.ValidateFormat(format))
+	}
+
+	assert.EqualError(t, writer.ValidateFormat("HTML"), "'HTML' is not a valid format. Valid values are markdown, html, asciidoc, rst, text")
+}
+
+func Test_FileNameKeepsTheMarkdownChangelog(t *testing.T) {
+	assert.Equal(t, "CHANGELOG.md", writer.FileName("CHANGELOG.md", writer.FormatMarkdown))
+	assert.Equal(t, "CHANGELOG.html", writer.FileName("CHANGELOG.md", writer.FormatHTML))
+	assert.Equal(t, "services/api/CHANGELOG.adoc", writer.FileName("services/api/CHANGELOG.md", writer.FormatAsciiDoc))
+	assert.Equal(t, "docs/changes.rst", writer.FileName("docs/changes.md", writer.FormatRST))
+	assert.Equal(t, "CHANGELOG.txt", writer.FileName("CHANGELOG", writer.FormatText))
+}
+
+func Test_RenderKeepsTheContentOfAParsedChangelog(t *testing.T) {
+	p := parser.NewParser("../../pkg/parser/testdata/hand_edited.md", "chelnak", "gh-changelog")
+	cl, err := p.Parse()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writer.Render(&buf, writer.FormatHTML, "", cl))
+
+	out := buf.String()
+	assert.Contains(t, out, "<h1>Changelog</h1>\n<p>All notable changes to this project will be documented in this file.</p>")
+	assert.Contains(t, out, "<p>Releases before v0.1.0 are not listed here.</p>")
+	assert.NotContains(t, out, "markdownlint")
+	assert.Contains(t, out, "<h2 id=\"unreleased\">Unreleased</h2>\n<p>These changes will be in the next release.</p>")
+	assert.Contains(t, out, "<p>This release fixes a regression in v0.15.0.</p>\n<p>Upgrading is recommended.</p>")
+	assert.Contains(t, out, "<h3>Added</h3>\n<p>Thanks to everyone who helped with this release.</p>")
+	assert.Contains(t, out, `<li><p>Improve sections ordering <a href="https://github.com/chelnak/gh-changelog/pull/139">#139</a>`)
+	assert.Contains(t, out, "<p>Sections are now ordered by the <code>section_order</code> configuration</p>")
+	assert.Contains(t, out, "<pre><code class=\"language-yaml\">section_order:\n## not a heading\n- added\n</code></pre>")
+
+	buf.Reset()
+	require.NoError(t, writer.WriteEntryHTML(&buf, cl, cl.GetEntries()[0]))
+	assert.Contains(t, buf.String(), "<p>This release fixes a regression in v0.15.0.</p>\n<p>Upgrading is recommended.</p>")
+
+	buf.Reset()
+	require.NoError(t, writer.Render(&buf, writer.FormatAsciiDoc, "", cl))
+
+	out = buf.String()
+	assert.Contains(t, out, "= Changelog\n\nAll notable changes to this project will be documented in this file.\n")
+	assert.Contains(t, out, "== Unreleased\n\nThese changes will be in the next release.\n\n* Fix no previous tag")
+	assert.Contains(t, out, "* Improve sections ordering https://github.com/chelnak/gh-changelog/pull/139[#139] (https://github.com/smortex[smortex])\n  - Sections are now ordered")
+
+	buf.Reset()
+	require.NoError(t, writer.Render(&buf, writer.FormatRST, "", cl))
+
+	out = buf.String()
+	assert.Contains(t, out, "Releases before v0.1.0 are not listed here.\n\nUnreleased\n----------\n\nThese changes will be in the next release.\n")
+	assert.Contains(t, out, "- Improve sections ordering `#139 <https://github.com/chelnak/gh-changelog/pull/139>`__ (`smortex <https://github.com/smortex>`__)\n  - Sections are now ordered")
+
+	buf.Reset()
+	require.NoError(t, writer.Render(&buf, writer.FormatText, "", cl))
+
+	out = buf.String()
+	assert.Contains(t, out, "Changelog\n\nAll notable changes to this project will be documented in this file.\n")
+	assert.Contains(t, out, "  - Improve sections ordering #139 (smortex)\n    - Sections are now ordered by the `section_order` configuration\n")
+	assert.Contains(t, out, "    - The order can be changed with:\n\n      ```yaml\n")
+}
@@ -0,0 +1,149 @@
+package writer
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"gopkg.in/yaml.v2"
+)
+
+// document is the stable schema that is used when a changelog is
+// serialized to JSON or YAML.
+type document struct {
+	RepoOwner  string          `json:"repoOwner" yaml:"repo_owner"`
+	RepoName   string          `json:"repoName" yaml:"repo_name"`
+	RepoHost   string          `json:"repoHost" yaml:"repo_host"`
+	Unreleased []documentItem  `json:"unreleased" yaml:"unreleased"`
+	Entries    []documentEntry `json:"entries" yaml:"entries"`
+}
+
+type documentEntry struct {
+	Tag          string                `json:"tag" yaml:"tag"`
+	PreviousTag  string                `json:"previousTag" yaml:"previous_tag"`
+	Date         string                `json:"date" yaml:"date"`
+	Description  string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Sections     []documentSection     `json:"sections" yaml:"sections"`
+	Contributors []documentContributor `json:"contributors,omitempty" yaml:"contributors,omitempty"`
+}
+
+type documentContributor struct {
+	Login     string `json:"login" yaml:"login"`
+	FirstTime bool   `json:"firstTime" yaml:"first_time"`
+}
+
+type documentSection struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Items       []documentItem `json:"items" yaml:"items"`
+}
+
+type documentItem struct {
+	Title    string   `json:"title,omitempty" yaml:"title,omitempty"`
+	Number   int      `json:"number,omitempty" yaml:"number,omitempty"`
+	Author   string   `json:"author,omitempty" yaml:"author,omitempty"`
+	URL      string   `json:"url,omitempty" yaml:"url,omitempty"`
+	Labels   []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	MergeSha string   `json:"mergeSha,omitempty" yaml:"merge_sha,omitempty"`
+	MergedAt string   `json:"mergedAt,omitempty" yaml:"merged_at,omitempty"`
+	Text     string   `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+func newDocument(changelog changelog.Changelog) document {
+	doc := document{
+		RepoOwner:  changelog.GetRepoOwner(),
+		RepoName:   changelog.GetRepoName(),
+		RepoHost:   changelog.GetRepoHost(),
+		Unreleased: newDocumentItems(changelog.GetUnreleased()),
+		Entries:    []documentEntry{},
+	}
+
+	for _, e := range changelog.GetEntries() {
+		doc.Entries = append(doc.Entries, newDocumentEntry(e))
+	}
+
+	return doc
+}
+
+func newDocumentEntry(e *entry.Entry) documentEntry {
+	previousTag := e.PrevTag
+	if previousTag == "" && e.Previous != nil {
+		previousTag = e.Previous.Tag
+	}
+
+	de := documentEntry{
+		Tag:         e.Tag,
+		PreviousTag: previousTag,
+		Description: e.Description,
+		Sections:    []documentSection{},
+	}
+
+	// Entries that were read from a changelog without a date have a zero date.
+	if !e.Date.IsZero() {
+		de.Date = e.Date.Format("2006-01-02")
+	}
+
+	for _, s := range e.Sections {
+		if len(s.Items) == 0 && s.Description == "" {
+			continue
+		}
+
+		de.Sections = append(de.Sections, documentSection{
+			Name:        s.Name,
+			Description: s.Description,
+			Items:       newDocumentItems(s.Items),
+		})
+	}
+
+	for _, c := range e.Contributors {
+		de.Contributors = append(de.Contributors, documentContributor{
+			Login:     c.Login,
+			FirstTime: c.FirstTime,
+		})
+	}
+
+	return de
+}
+
+func newDocumentItems(items []entry.Item) []documentItem {
+	documentItems := []documentItem{}
+	for _, item := range items {
+		di := documentItem{
+			Title:    item.Title,
+			Number:   item.Number,
+			Author:   item.Author,
+			URL:      item.URL,
+			Labels:   item.Labels,
+			MergeSha: item.MergeSha,
+			Text:     item.Text,
+		}
+
+		if !item.MergedAt.IsZero() {
+			di.MergedAt = item.MergedAt.Format(time.RFC3339)
+		}
+
+		documentItems = append(documentItems, di)
+	}
+
+	return documentItems
+}
+
+// WriteJSON serializes the changelog to JSON and writes it to the given writer.
+func WriteJSON(writer io.Writer, changelog changelog.Changelog) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newDocument(changelog))
+}
+
+// WriteYAML serializes the changelog to YAML and writes it to the given writer.
+func WriteYAML(writer io.Writer, changelog changelog.Changelog) error {
+	b, err := yaml.Marshal(newDocument(changelog))
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(b)
+	return err
+}
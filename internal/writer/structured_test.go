@@ -0,0 +1,132 @@
+package writer_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStructuredChangelog() changelog.Changelog {
+	cl := changelog.NewChangelog(repoOwner, repoName)
+
+	one := entry.NewEntry("v1.0.0", time.Date(2022, 4, 18, 0, 0, 0, 0, time.UTC))
+	_ = one.Append("Added", entry.Item{
+		Title:    "Added 1",
+		Number:   1,
+		Author:   "test-user",
+		URL:      "https://github.com/repo-owner/repo-name/pull/1",
+		Labels:   []string{"enhancement"},
+		MergeSha: "abc123",
+		MergedAt: time.Date(2022, 4, 17, 12, 0, 0, 0, time.UTC),
+	})
+	_ = one.Append("Fixed", entry.Item{Text: "Fixed 1"})
+
+	// Entries that are read from a changelog may not have a date.
+	two := entry.NewEntry("v0.9.0", time.Time{})
+	two.PrevTag = "v0.8.0"
+
+	cl.Insert(one)
+	cl.Insert(two)
+	cl.AddUnreleased([]entry.Item{{Text: "Unreleased 1"}})
+
+	return cl
+}
+
+func Test_ItWritesOutAChangelogAsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.WriteJSON(&buf, setupStructuredChangelog())
+	assert.NoError(t, err)
+
+	expected := `{
+  "repoOwner": "repo-owner",
+  "repoName": "repo-name",
+  "repoHost": "github.com",
+  "unreleased": [
+    {
+      "text": "Unreleased 1"
+    }
+  ],
+  "entries": [
+    {
+      "tag": "v1.0.0",
+      "previousTag": "v0.9.0",
+      "date": "2022-04-18",
+      "sections": [
+        {
+          "name": "Added",
+          "items": [
+            {
+              "title": "Added 1",
+              "number": 1,
+              "author": "test-user",
+              "url": "https://github.com/repo-owner/repo-name/pull/1",
+              "labels": [
+                "enhancement"
+              ],
+              "mergeSha": "abc123",
+              "mergedAt": "2022-04-17T12:00:00Z"
+            }
+          ]
+        },
+        {
+          "name": "Fixed",
+          "items": [
+            {
+              "text": "Fixed 1"
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "tag": "v0.9.0",
+      "previousTag": "v0.8.0",
+      "date": "",
+      "sections": []
+    }
+  ]
+}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func Test_ItWritesOutAChangelogAsYAML(t *testing.T) {
+	var buf bytes.Buffer
+	err := writer.WriteYAML(&buf, setupStructuredChangelog())
+	assert.NoError(t, err)
+
+	expected := `repo_owner: repo-owner
+repo_name: repo-name
+repo_host: github.com
+unreleased:
+- text: Unreleased 1
+entries:
+- tag: v1.0.0
+  previous_tag: v0.9.0
+  date: "2022-04-18"
+  sections:
+  - name: Added
+    items:
+    - title: Added 1
+      number: 1
+      author: test-user
+      url: https://github.com/repo-owner/repo-name/pull/1
+      labels:
+      - enhancement
+      merge_sha: abc123
+      merged_at: "2022-04-17T12:00:00Z"
+  - name: Fixed
+    items:
+    - text: Fixed 1
+- tag: v0.9.0
+  previous_tag: v0.8.0
+  date: ""
+  sections: []
+`
+	assert.Equal(t, expected, buf.String())
+}
@@ -3,123 +3,95 @@
 package writer
 
 import (
+	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"text/template"
+	"time"
 
 	"github.com/chelnak/gh-changelog/internal/gitclient"
 	"github.com/chelnak/gh-changelog/pkg/changelog"
+	"github.com/chelnak/gh-changelog/pkg/entry"
 )
 
-const tmplStandard = `<!-- markdownlint-disable MD024 -->
+const tmplStandard = `{{- with .GetPreamble }}{{ . }}{{ else -}}
+<!-- markdownlint-disable MD024 -->
 # Changelog
 
 All notable changes to this project will be documented in this file.
 
 The format is based on [Keep a Changelog](http://keepachangelog.com/en/1.0.0/) and this project adheres to [Semantic Versioning](http://semver.org).
+{{- end }}
 
 {{- $unreleased := .GetUnreleased }}
-{{- if $unreleased }}
+{{- if or $unreleased .GetUnreleasedDescription }}
 
 ## Unreleased
+{{- with .GetUnreleasedDescription }}
+
+{{ . }}
+{{- end }}
 {{range $unreleased }}
-- {{.}}
+- {{formatItem .}}
 {{- end -}}
 {{- end }}
 {{range .GetEntries}}
-## [{{.Tag}}](https://github.com/{{$.GetRepoOwner}}/{{$.GetRepoName}}/tree/{{.Tag}}) - {{.Date.Format "2006-01-02"}}
+## [{{.Tag}}]({{tagURL .Tag}}) - {{formatDate .Date}}
 {{ if .Previous }}
-[Full Changelog](https://github.com/{{$.GetRepoOwner}}/{{$.GetRepoName}}/compare/{{.Previous.Tag}}...{{.Tag}})
+[Full Changelog]({{compareURL (or .PrevTag .Previous.Tag) .Tag}})
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
 {{else}}
-[Full Changelog](https://github.com/{{$.GetRepoOwner}}/{{$.GetRepoName}}/compare/{{if .PrevTag }}{{.PrevTag}}{{else}}{{getFirstCommit}}{{end}}...{{.Tag}})
+[Full Changelog]({{compareURL (or .PrevTag getFirstCommit) .Tag}})
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
 {{- end -}}
 
-{{- if .Security }}
-### Security
-{{range .Security}}
-- {{.}}
-{{- end}}
+{{- range .Sections }}
+{{- if or .Items .Description }}
+### {{.Name}}
+{{with .Description}}
+{{ . }}
 {{end}}
-{{- if .Changed }}
-### Changed
-{{range .Changed}}
-- {{.}}
+{{- range .Items}}
+- {{formatItem .}}
 {{- end}}
 {{end}}
-{{- if .Removed }}
-### Removed
-{{range .Removed}}
-- {{.}}
 {{- end}}
-{{end}}
-{{- if .Deprecated }}
-### Deprecated
-{{range .Deprecated}}
-- {{.}}
-{{- end}}
-{{end}}
-{{- if .Added }}
-### Added
-{{range .Added}}
-- {{.}}
-{{- end}}
-{{end}}
-{{- if .Fixed }}
-### Fixed
-{{range .Fixed}}
-- {{.}}
-{{- end}}
-{{end}}
-{{- if .Other }}
-### Other
-{{range .Other}}
-- {{.}}
+{{- with .Contributors }}
+### Contributors
+{{range .}}
+- {{formatContributor .}}
 {{- end}}
 {{end}}
 {{- end}}
 `
 
 const tmplNotes = `{{range .GetEntries }}
-{{- if .Security }}
-### Security
-{{range .Security}}
-- {{.}}
-{{- end}}
+{{- with .Description }}
+{{ . }}
+{{ end }}
+{{- range .Sections }}
+{{- if or .Items .Description }}
+### {{.Name}}
+{{with .Description}}
+{{ . }}
 {{end}}
-{{- if .Changed }}
-### Changed
-{{range .Changed}}
-- {{.}}
+{{- range .Items}}
+- {{formatItem .}}
 {{- end}}
 {{end}}
-{{- if .Removed }}
-### Removed
-{{range .Removed}}
-- {{.}}
 {{- end}}
-{{end}}
-{{- if .Deprecated }}
-### Deprecated
-{{range .Deprecated}}
-- {{.}}
-{{- end}}
-{{end}}
-{{- if .Added }}
-### Added
-{{range .Added}}
-- {{.}}
-{{- end}}
-{{end}}
-{{- if .Fixed }}
-### Fixed
-{{range .Fixed}}
-- {{.}}
-{{- end}}
-{{end}}
-{{- if .Other }}
-### Other
-{{range .Other}}
-- {{.}}
+{{- with .Contributors }}
+### Contributors
+{{range .}}
+- {{formatContributor .}}
 {{- end}}
 {{end}}
 {{- end}}`
@@ -129,20 +101,162 @@ const (
 	TmplSrcNotes    = tmplNotes
 )
 
+// Write renders the changelog with the given template source and writes the
+// result to writer.
+//
+// Templates are executed against a changelog.Changelog. The following
+// functions are also available to templates:
+//
+//	getFirstCommit              the hash of the first commit in the repository
+//	formatDate <date>           formats a date as 2006-01-02
+//	repoURL                     the URL of the repository
+//	tagURL <tag>                the URL of a tag
+//	compareURL <from> <to>      the URL that compares two refs
+//	pullRequestURL <number>     the URL of a pull request
+//	userURL <login>             the URL of a user profile
+//	formatItem <item>           formats an item as a markdown line
+//	formatContributor <c>       formats a contributor as a markdown line
 func Write(writer io.Writer, tmplSrc string, changelog changelog.Changelog) error {
-	tmpl, err := template.New("changelog").Funcs(template.FuncMap{
-		"getFirstCommit": func() string {
-			git := gitclient.NewGitClient(exec.Command)
-			commit, err := git.GetFirstCommit()
-			if err != nil {
-				return ""
-			}
-			return commit
-		},
-	}).Parse(tmplSrc)
+	tmpl, err := template.New("changelog").Funcs(funcMap(changelog)).Parse(tmplSrc)
 	if err != nil {
 		return err
 	}
 
 	return tmpl.Execute(writer, changelog)
 }
+
+// ResolveTemplate returns the contents of the template file at the given
+// path. If path is empty, the standard template is returned. The template is
+// parsed so that a syntax error is found before the changelog is built.
+func ResolveTemplate(path string) (string, error) {
+	if path == "" {
+		return TmplSrcStandard, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("could not read template: %s", err)
+	}
+
+	if _, err := template.New("changelog").Funcs(funcMap(nil)).Parse(string(data)); err != nil {
+		return "", fmt.Errorf("could not parse template: %s", err)
+	}
+
+	return string(data), nil
+}
+
+// RepoURL returns the URL of the repository that the changelog belongs to.
+func RepoURL(changelog changelog.Changelog) string {
+	return links{changelog: changelog}.repoURL()
+}
+
+// TagURL returns the URL of a tag in the repository that the changelog
+// belongs to.
+func TagURL(changelog changelog.Changelog, tag string) string {
+	return links{changelog: changelog}.tagURL(tag)
+}
+
+// links builds the URLs of the repository that the changelog belongs to.
+type links struct {
+	changelog changelog.Changelog
+}
+
+func (l links) repoURL() string {
+	return fmt.Sprintf("https://%s/%s/%s", l.changelog.GetRepoHost(), l.changelog.GetRepoOwner(), l.changelog.GetRepoName())
+}
+
+func (l links) tagURL(tag string) string {
+	return fmt.Sprintf("%s/tree/%s", l.repoURL(), tag)
+}
+
+func (l links) compareURL(from, to string) string {
+	return fmt.Sprintf("%s/compare/%s...%s", l.repoURL(), from, to)
+}
+
+func (l links) pullRequestURL(number int) string {
+	return fmt.Sprintf("%s/pull/%d", l.repoURL(), number)
+}
+
+func (l links) commitURL(sha string) string {
+	return fmt.Sprintf("%s/commit/%s", l.repoURL(), sha)
+}
+
+func (l links) userURL(login string) string {
+	return fmt.Sprintf("https://%s/%s", l.changelog.GetRepoHost(), login)
+}
+
+// itemURL returns the URL of the pull request of an item.
+func (l links) itemURL(item entry.Item) string {
+	if item.URL != "" {
+		return item.URL
+	}
+
+	return l.pullRequestURL(item.Number)
+}
+
+// isCommit reports whether an item is a commit that was pushed without a pull
+// request.
+func isCommit(item entry.Item) bool {
+	return item.Number == 0 && item.Text == "" && item.MergeSha != ""
+}
+
+// shortSha returns the abbreviated form of a commit sha.
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+func getFirstCommit() string {
+	git := gitclient.NewGitClient(exec.Command)
+	commit, err := git.GetFirstCommit()
+	if err != nil {
+		return ""
+	}
+	return commit
+}
+
+func formatDate(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// firstContribution returns the note that is added to first-time
+// contributors.
+func firstContribution(c entry.Contributor) string {
+	if c.FirstTime {
+		return " made their first contribution"
+	}
+
+	return ""
+}
+
+func funcMap(changelog changelog.Changelog) template.FuncMap {
+	l := links{changelog: changelog}
+
+	return template.FuncMap{
+		"getFirstCommit": getFirstCommit,
+		"formatDate":     formatDate,
+		"repoURL":        l.repoURL,
+		"tagURL":         l.tagURL,
+		"compareURL":     l.compareURL,
+		"pullRequestURL": l.pullRequestURL,
+		"userURL":        l.userURL,
+		"formatItem": func(item entry.Item) string {
+			// Items read from an existing changelog are written as they were found.
+			if item.Text != "" {
+				return item.Text
+			}
+
+			if isCommit(item) {
+				return fmt.Sprintf("%s [%s](%s)", item.Title, shortSha(item.MergeSha), l.commitURL(item.MergeSha))
+			}
+
+			return fmt.Sprintf("%s [#%d](%s) ([%s](%s))", item.Title, item.Number, l.itemURL(item), item.Author, l.userURL(item.Author))
+		},
+		"formatContributor": func(c entry.Contributor) string {
+			return fmt.Sprintf("[@%s](%s)%s", c.Login, l.userURL(c.Login), firstContribution(c))
+		},
+	}
+}
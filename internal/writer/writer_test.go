@@ -2,6 +2,8 @@ package writer_test
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
@@ -21,15 +23,18 @@ func Test_ItWritesOutAChangelogInTheCorrectFormat(t *testing.T) {
 	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
 
 	one := entry.Entry{
-		Tag:        "v1.0.0",
-		Date:       time.Now(),
-		Added:      []string{"Added 1", "Added 2"},
-		Changed:    []string{"Changed 1", "Changed 2"},
-		Deprecated: []string{"Deprecated 1", "Deprecated 2"},
-		Removed:    []string{"Removed 1", "Removed 2"},
-		Fixed:      []string{"Fixed 1", "Fixed 2"},
-		Security:   []string{"Security 1", "Security 2"},
-		Other:      []string{"Other 1", "Other 2"},
+		Tag:  "v1.0.0",
+		Date: time.Now(),
+		Sections: []entry.Section{
+			{Name: "Added", Items: []entry.Item{{Text: "Added 1"}, {Text: "Added 2"}}},
+			{Name: "Changed", Items: []entry.Item{{Text: "Changed 1"}, {Text: "Changed 2"}}},
+			{Name: "Deprecated", Items: []entry.Item{{Text: "Deprecated 1"}, {Text: "Deprecated 2"}}},
+			{Name: "Removed", Items: []entry.Item{{Text: "Removed 1"}, {Text: "Removed 2"}}},
+			{Name: "Fixed", Items: []entry.Item{{Text: "Fixed 1"}, {Text: "Fixed 2"}}},
+			{Name: "Security", Items: []entry.Item{{Text: "Security 1"}, {Text: "Security 2"}}},
+			{Name: "Other", Items: []entry.Item{{Text: "Other 1"}, {Text: "Other 2"}}},
+			{Name: "Performance", Items: []entry.Item{{Text: "Performance 1"}}},
+		},
 	}
 
 	two := one
@@ -37,7 +42,7 @@ func Test_ItWritesOutAChangelogInTheCorrectFormat(t *testing.T) {
 	one.Previous = &two
 
 	mockChangelog.Insert(one)
-	mockChangelog.AddUnreleased([]string{"Unreleased 1", "Unreleased 2"})
+	mockChangelog.AddUnreleased([]entry.Item{{Text: "Unreleased 1"}, {Text: "Unreleased 2"}})
 
 	var buf bytes.Buffer
 	err := writer.Write(&buf, writer.TmplSrcStandard, mockChangelog)
@@ -59,6 +64,9 @@ func Test_ItWritesOutAChangelogInTheCorrectFormat(t *testing.T) {
 	assert.Regexp(t, "- Other 1", buf.String())
 	assert.Regexp(t, "- Other 2", buf.String())
 
+	assert.Regexp(t, "### Performance", buf.String())
+	assert.Regexp(t, "- Performance 1", buf.String())
+
 	buf.Reset()
 	err = writer.Write(&buf, writer.TmplSrcNotes, mockChangelog)
 
@@ -67,3 +75,179 @@ func Test_ItWritesOutAChangelogInTheCorrectFormat(t *testing.T) {
 	assert.NotRegexp(t, regexp.MustCompile(`## \[v1.0.0\]\(https:\/\/github.com\/repo-owner\/repo-name\/tree\/v1.0.0\)`), buf.String())
 	assert.NotRegexp(t, regexp.MustCompile(`\[Full Changelog\]\(https:\/\/github.com\/repo-owner\/repo-name\/compare\/v0.9.0\.\.\.v1.0.0\)`), buf.String())
 }
+
+func Test_ItFormatsItemsBuiltFromPullRequests(t *testing.T) {
+	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
+
+	e := entry.NewEntry("v1.0.0", time.Now())
+	e.PrevTag = "v0.9.0"
+	assert.NoError(t, e.Append("Added", entry.Item{Title: "Add a feature", Number: 1, Author: "test-user"}))
+	assert.NoError(t, e.Append("Fixed", entry.Item{Title: "Fix a bug", Number: 2, Author: "test-user", URL: "https://example.com/2"}))
+	mockChangelog.Insert(e)
+
+	var buf bytes.Buffer
+	err := writer.Write(&buf, writer.TmplSrcNotes, mockChangelog)
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "- Add a feature [#1](https://github.com/repo-owner/repo-name/pull/1) ([test-user](https://github.com/test-user))")
+	assert.Contains(t, buf.String(), "- Fix a bug [#2](https://example.com/2) ([test-user](https://github.com/test-user))")
+}
+
+func Test_ItFormatsItemsBuiltFromCommits(t *testing.T) {
+	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
+
+	e := entry.NewEntry("v1.0.0", time.Now())
+	e.PrevTag = "v0.9.0"
+	assert.NoError(t, e.Append("Other", entry.Item{Title: "Fix a typo", MergeSha: "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2"}))
+	mockChangelog.Insert(e)
+
+	var buf bytes.Buffer
+	err := writer.Write(&buf, writer.TmplSrcNotes, mockChangelog)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "- Fix a typo [c3d4e5f](https://github.com/repo-owner/repo-name/commit/c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2)")
+
+	buf.Reset()
+	err = writer.Render(&buf, writer.FormatHTML, "", mockChangelog)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `<li>Fix a typo <a href="https://github.com/repo-owner/repo-name/commit/c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2">c3d4e5f</a></li>`)
+
+	buf.Reset()
+	err = writer.Render(&buf, writer.FormatText, "", mockChangelog)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Fix a typo c3d4e5f")
+}
+
+func Test_ItWritesOutContributors(t *testing.T) {
+	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
+
+	e := entry.NewEntry("v1.0.0", time.Now())
+	e.PrevTag = "v0.9.0"
+	assert.NoError(t, e.Append("Added", entry.Item{Title: "Add a feature", Number: 1, Author: "new-user"}))
+	e.Contributors = []entry.Contributor{
+		{Login: "new-user", FirstTime: true},
+		{Login: "test-user"},
+	}
+	mockChangelog.Insert(e)
+
+	contributors := "### Contributors\n\n" +
+		"- [@new-user](https://github.com/new-user) made their first contribution\n" +
+		"- [@test-user](https://github.com/test-user)\n"
+
+	var buf bytes.Buffer
+	err := writer.Write(&buf, writer.TmplSrcStandard, mockChangelog)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "([new-user](https://github.com/new-user))\n\n"+contributors)
+
+	buf.Reset()
+	err = writer.Write(&buf, writer.TmplSrcNotes, mockChangelog)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), contributors)
+
+	buf.Reset()
+	err = writer.Render(&buf, writer.FormatHTML, "", mockChangelog)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "<h3>Contributors</h3>\n<ul>\n<li><a href=\"https://github.com/new-user\">@new-user</a> made their first contribution</li>")
+}
+
+func Test_ItWritesOutAChangelogWithACustomTemplate(t *testing.T) {
+	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
+
+	e := entry.NewEntry("v1.0.0", time.Date(2022, 4, 18, 0, 0, 0, 0, time.UTC))
+	e.PrevTag = "v0.9.0"
+	assert.NoError(t, e.Append("Added", entry.Item{Text: "Added 1"}))
+	mockChangelog.Insert(e)
+
+	tmplPath := filepath.Join(t.TempDir(), "template.tmpl")
+	tmplSrc := `{{range .GetEntries}}# {{.Tag}} ({{formatDate .Date}})
+{{tagURL .Tag}}
+{{compareURL .PrevTag .Tag}}
+{{range .Sections}}{{.Name}}:{{range .Items}} {{formatItem .}}{{end}}{{end}}
+{{pullRequestURL 1}} {{userURL "test-user"}}
+{{end}}`
+	assert.NoError(t, os.WriteFile(tmplPath, []byte(tmplSrc), 0600))
+
+	src, err := writer.ResolveTemplate(tmplPath)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = writer.Write(&buf, src, mockChangelog)
+	assert.NoError(t, err)
+
+	expected := `# v1.0.0 (2022-04-18)
+https://github.com/repo-owner/repo-name/tree/v1.0.0
+https://github.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0
+Added: Added 1
+https://github.com/repo-owner/repo-name/pull/1 https://github.com/test-user
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func Test_ResolveTemplateReturnsTheStandardTemplateByDefault(t *testing.T) {
+	src, err := writer.ResolveTemplate("")
+	assert.NoError(t, err)
+	assert.Equal(t, writer.TmplSrcStandard, src)
+
+	_, err = writer.ResolveTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.Error(t, err)
+}
+
+func Test_ResolveTemplateReturnsAnErrorForAnInvalidTemplate(t *testing.T) {
+	tmplPath := filepath.Join(t.TempDir(), "template.tmpl")
+	assert.NoError(t, os.WriteFile(tmplPath, []byte("{{range .GetEntries}}{{.Tag}}"), 0600))
+
+	_, err := writer.ResolveTemplate(tmplPath)
+	assert.ErrorContains(t, err, "could not parse template")
+}
+
+func Test_ItWritesOutDescriptions(t *testing.T) {
+	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
+	mockChangelog.SetPreamble("# Release history")
+
+	e := entry.NewEntry("v1.0.0", time.Now())
+	e.PrevTag = "v0.9.0"
+	e.Description = "The first stable release."
+	e.Sections = []entry.Section{
+		{Name: "Added", Description: "Everything is new.", Items: []entry.Item{{Text: "Added 1"}}},
+	}
+	mockChangelog.Insert(e)
+
+	var buf bytes.Buffer
+	err := writer.Write(&buf, writer.TmplSrcStandard, mockChangelog)
+	assert.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^# Release history\n\n## \[v1.0.0\]`), buf.String())
+	assert.Contains(t, buf.String(), "compare/v0.9.0...v1.0.0)\n\nThe first stable release.\n")
+	assert.Contains(t, buf.String(), "### Added\n\nEverything is new.\n\n- Added 1\n")
+
+	buf.Reset()
+	err = writer.Write(&buf, writer.TmplSrcNotes, mockChangelog)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "\nThe first stable release.\n\n### Added\n\nEverything is new.\n\n- Added 1\n", buf.String())
+
+	buf.Reset()
+	err = writer.WriteJSON(&buf, mockChangelog)
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `"description": "The first stable release."`)
+	assert.Contains(t, buf.String(), `"description": "Everything is new."`)
+}
+
+func Test_ItWritesOutLinksForTheRepositoryHost(t *testing.T) {
+	mockChangelog := changelog.NewChangelog(repoOwner, repoName)
+	mockChangelog.SetRepoHost("ghes.example.com")
+
+	e := entry.NewEntry("v1.0.0", time.Now())
+	e.PrevTag = "v0.9.0"
+	assert.NoError(t, e.Append("Added", entry.Item{Title: "Add a feature", Number: 1, Author: "test-user"}))
+	mockChangelog.Insert(e)
+
+	var buf bytes.Buffer
+	err := writer.Write(&buf, writer.TmplSrcStandard, mockChangelog)
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "## [v1.0.0](https://ghes.example.com/repo-owner/repo-name/tree/v1.0.0)")
+	assert.Contains(t, buf.String(), "[Full Changelog](https://ghes.example.com/repo-owner/repo-name/compare/v0.9.0...v1.0.0)")
+	assert.Contains(t, buf.String(), "- Add a feature [#1](https://ghes.example.com/repo-owner/repo-name/pull/1) ([test-user](https://ghes.example.com/test-user))")
+	assert.NotContains(t, buf.String(), "https://github.com")
+}
@@ -13,7 +13,7 @@ type Changelog struct {
 }
 
 // AddUnreleased provides a mock function with given fields: _a0
-func (_m *Changelog) AddUnreleased(_a0 []string) {
+func (_m *Changelog) AddUnreleased(_a0 []entry.Item) {
 	_m.Called(_a0)
 }
 
@@ -37,6 +37,42 @@ func (_m *Changelog) GetEntries() []*entry.Entry {
 	return r0
 }
 
+// GetPreamble provides a mock function with given fields:
+func (_m *Changelog) GetPreamble() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreamble")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GetRepoHost provides a mock function with given fields:
+func (_m *Changelog) GetRepoHost() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepoHost")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetRepoName provides a mock function with given fields:
 func (_m *Changelog) GetRepoName() string {
 	ret := _m.Called()
@@ -74,25 +110,43 @@ func (_m *Changelog) GetRepoOwner() string {
 }
 
 // GetUnreleased provides a mock function with given fields:
-func (_m *Changelog) GetUnreleased() []string {
+func (_m *Changelog) GetUnreleased() []entry.Item {
 	ret := _m.Called()
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUnreleased")
 	}
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func() []string); ok {
+	var r0 []entry.Item
+	if rf, ok := ret.Get(0).(func() []entry.Item); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).([]entry.Item)
 		}
 	}
 
 	return r0
 }
 
+// GetUnreleasedDescription provides a mock function with given fields:
+func (_m *Changelog) GetUnreleasedDescription() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnreleasedDescription")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Head provides a mock function with given fields:
 func (_m *Changelog) Head() *entry.Entry {
 	ret := _m.Called()
@@ -118,6 +172,21 @@ func (_m *Changelog) Insert(_a0 entry.Entry) {
 	_m.Called(_a0)
 }
 
+// SetPreamble provides a mock function with given fields: _a0
+func (_m *Changelog) SetPreamble(_a0 string) {
+	_m.Called(_a0)
+}
+
+// SetRepoHost provides a mock function with given fields: _a0
+func (_m *Changelog) SetRepoHost(_a0 string) {
+	_m.Called(_a0)
+}
+
+// SetUnreleasedDescription provides a mock function with given fields: _a0
+func (_m *Changelog) SetUnreleasedDescription(_a0 string) {
+	_m.Called(_a0)
+}
+
 // Tail provides a mock function with given fields:
 func (_m *Changelog) Tail() *entry.Entry {
 	ret := _m.Called()
@@ -3,9 +3,10 @@
 package mocks
 
 import (
-	time "time"
-
+	gitclient "github.com/chelnak/gh-changelog/internal/gitclient"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // GitClient is an autogenerated mock type for the GitClient type
@@ -13,6 +14,124 @@ type GitClient struct {
 	mock.Mock
 }
 
+// GetChangedFiles provides a mock function with given fields: hash
+func (_m *GitClient) GetChangedFiles(hash string) ([]string, error) {
+	ret := _m.Called(hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChangedFiles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommit provides a mock function with given fields: hash
+func (_m *GitClient) GetCommit(hash string) (gitclient.Commit, error) {
+	ret := _m.Called(hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommit")
+	}
+
+	var r0 gitclient.Commit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (gitclient.Commit, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(string) gitclient.Commit); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(gitclient.Commit)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommitsBetween provides a mock function with given fields: from, to
+func (_m *GitClient) GetCommitsBetween(from string, to string) ([]gitclient.Commit, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommitsBetween")
+	}
+
+	var r0 []gitclient.Commit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]gitclient.Commit, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []gitclient.Commit); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gitclient.Commit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommitsBetweenDates provides a mock function with given fields: from, to
+func (_m *GitClient) GetCommitsBetweenDates(from time.Time, to time.Time) ([]gitclient.Commit, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommitsBetweenDates")
+	}
+
+	var r0 []gitclient.Commit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]gitclient.Commit, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []gitclient.Commit); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gitclient.Commit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDateOfHash provides a mock function with given fields: hash
 func (_m *GitClient) GetDateOfHash(hash string) (time.Time, error) {
 	ret := _m.Called(hash)
@@ -69,6 +188,36 @@ func (_m *GitClient) GetFirstCommit() (string, error) {
 	return r0, r1
 }
 
+// GetFirstParentCommitsBetween provides a mock function with given fields: from, to
+func (_m *GitClient) GetFirstParentCommitsBetween(from string, to string) ([]gitclient.Commit, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFirstParentCommitsBetween")
+	}
+
+	var r0 []gitclient.Commit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]gitclient.Commit, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []gitclient.Commit); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gitclient.Commit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastCommit provides a mock function with given fields:
 func (_m *GitClient) GetLastCommit() (string, error) {
 	ret := _m.Called()
@@ -97,6 +246,66 @@ func (_m *GitClient) GetLastCommit() (string, error) {
 	return r0, r1
 }
 
+// GetTags provides a mock function with given fields:
+func (_m *GitClient) GetTags() ([]gitclient.Tag, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTags")
+	}
+
+	var r0 []gitclient.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]gitclient.Tag, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []gitclient.Tag); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gitclient.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTagsReachableFrom provides a mock function with given fields: ref
+func (_m *GitClient) GetTagsReachableFrom(ref string) ([]string, error) {
+	ret := _m.Called(ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagsReachableFrom")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(ref)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewGitClient creates a new instance of GitClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewGitClient(t interface {
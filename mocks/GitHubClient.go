@@ -14,6 +14,92 @@ type GitHubClient struct {
 	mock.Mock
 }
 
+// GetChangedFiles provides a mock function with given fields: number
+func (_m *GitHubClient) GetChangedFiles(number int) ([]string, error) {
+	ret := _m.Called(number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChangedFiles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]string, error)); ok {
+		return rf(number)
+	}
+	if rf, ok := ret.Get(0).(func(int) []string); ok {
+		r0 = rf(number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFirstCommit provides a mock function with given fields:
+func (_m *GitHubClient) GetFirstCommit() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFirstCommit")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFirstMergeDate provides a mock function with given fields: user
+func (_m *GitHubClient) GetFirstMergeDate(user string) (time.Time, error) {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFirstMergeDate")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (time.Time, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPullRequestsBetweenDates provides a mock function with given fields: from, to
 func (_m *GitHubClient) GetPullRequestsBetweenDates(from time.Time, to time.Time) ([]githubclient.PullRequest, error) {
 	ret := _m.Called(from, to)
@@ -44,6 +130,24 @@ func (_m *GitHubClient) GetPullRequestsBetweenDates(from time.Time, to time.Time
 	return r0, r1
 }
 
+// GetRepoHost provides a mock function with given fields:
+func (_m *GitHubClient) GetRepoHost() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepoHost")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetRepoName provides a mock function with given fields:
 func (_m *GitHubClient) GetRepoName() string {
 	ret := _m.Called()
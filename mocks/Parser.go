@@ -42,6 +42,36 @@ func (_m *Parser) Parse() (changelog.Changelog, error) {
 	return r0, r1
 }
 
+// ParseAll provides a mock function with given fields:
+func (_m *Parser) ParseAll() (changelog.Changelog, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAll")
+	}
+
+	var r0 changelog.Changelog
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (changelog.Changelog, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() changelog.Changelog); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(changelog.Changelog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewParser creates a new instance of Parser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewParser(t interface {
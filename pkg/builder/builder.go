@@ -5,25 +5,59 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/conventional"
 	"github.com/chelnak/gh-changelog/internal/gitclient"
 	"github.com/chelnak/gh-changelog/internal/githubclient"
 	"github.com/chelnak/gh-changelog/internal/logging"
 	"github.com/chelnak/gh-changelog/internal/utils"
+	"github.com/chelnak/gh-changelog/internal/version"
 	"github.com/chelnak/gh-changelog/pkg/changelog"
 	"github.com/chelnak/gh-changelog/pkg/entry"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 var Now = time.Now // must be a better way to stub this
 
+// pullRequestSubjectRegex matches the (#123) suffix that GitHub adds to the
+// subject of a squash merged pull request.
+var pullRequestSubjectRegex = regexp.MustCompile(`\(#\d+\)$`)
+
+const (
+	// SourceGitHub builds the changelog from data retrieved from the GitHub API.
+	SourceGitHub = "github"
+	// SourceGit builds the changelog from the local git repository only.
+	SourceGit = "git"
+
+	// NextVersionAuto calculates the next version from the unreleased entries.
+	NextVersionAuto = "auto"
+
+	// TagSortDate orders tags by the date they were created.
+	TagSortDate = "date"
+	// TagSortSemver orders tags by semantic version precedence.
+	TagSortSemver = "semver"
+)
+
 type BuilderOptions struct {
 	Logger        string
+	Source        string
 	NextVersion   string
 	FromVersion   string
 	LatestVersion bool
+	SinceVersion  string
+	Repo          string // [HOST/]OWNER/NAME, read from the API instead of the local clone
+	Package       string // the name of a package in the packages configuration
+	Line          string // a version line such as 1.x or 1.4.x
+	Branch        string // only tags that are reachable from the branch are used
+	FileName      string // the file that the changelog is written to, file_name from the configuration when empty
 	GitClient     gitclient.GitClient
 	GitHubClient  githubclient.GitHubClient
 }
@@ -35,33 +69,101 @@ func (bo *BuilderOptions) setupGitClient() {
 }
 
 func (bo *BuilderOptions) setupGitHubClient() error {
-	if bo.GitHubClient == nil {
-		client, err := githubclient.NewGitHubClient()
-		if err != nil {
-			return err
+	if bo.GitHubClient != nil {
+		return nil
+	}
+
+	var client githubclient.GitHubClient
+	var err error
+
+	switch bo.Source {
+	case "", SourceGitHub:
+		client, err = newGitHubClient(bo.Repo)
+	case SourceGit:
+		if bo.Repo != "" {
+			return fmt.Errorf("the '%s' source reads the local repository and can not be used with a repository", SourceGit)
 		}
-		bo.GitHubClient = client
+
+		client, err = githubclient.NewLocalClient(bo.GitClient)
+	default:
+		return fmt.Errorf("'%s' is not a valid source. Valid values are '%s' and '%s'", bo.Source, SourceGitHub, SourceGit)
+	}
+
+	if err != nil {
+		return err
 	}
 
+	bo.GitHubClient = client
+
 	return nil
 }
 
+// newGitHubClient returns a client for the given repository, or for the
+// repository in the current directory when repo is empty.
+func newGitHubClient(repo string) (githubclient.GitHubClient, error) {
+	if repo == "" {
+		return githubclient.NewGitHubClient()
+	}
+
+	repoContext, err := utils.ParseRepoContext(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return githubclient.NewGitHubClientForRepo(repoContext)
+}
+
 type Builder interface {
 	BuildChangelog() (changelog.Changelog, error)
+	NextVersion() (string, error)
 }
 
 type builder struct {
 	nextVersion   string
 	fromVersion   string
 	latestVersion bool
+	sinceVersion  string
+	remote        bool
+	firstCommit   string
+	tagPrefix     string
+	paths         []string
+	line          *versionLine
+	branch        string
+	fileName      string
 	tags          []githubclient.Tag
 	changelog     changelog.Changelog
 	git           gitclient.GitClient
 	github        githubclient.GitHubClient
 	logger        logging.Logger
+	firstMerges   map[string]time.Time // keyed by lower case login
 }
 
 func NewBuilder(options BuilderOptions) (Builder, error) {
+	if options.Repo != "" && configuration.Config.UseCommitGraph {
+		return nil, errors.New("use_commit_graph reads the local repository and can not be used with a repository")
+	}
+
+	if options.Repo != "" && configuration.Config.DirectCommits.Enabled {
+		return nil, errors.New("direct_commits reads the local repository and can not be used with a repository")
+	}
+
+	if options.Repo != "" && options.Branch != "" {
+		return nil, errors.New("a branch is read from the local repository and can not be used with a repository")
+	}
+
+	if options.Repo != "" && options.Line != "" {
+		return nil, errors.New("a version line uses the commit graph of the local repository and can not be used with a repository")
+	}
+
+	fileName, err := configuration.Config.GetFileName(options.Package)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.FileName != "" {
+		fileName = options.FileName
+	}
+
 	options.setupGitClient()
 
 	if err := options.setupGitHubClient(); err != nil {
@@ -72,14 +174,35 @@ func NewBuilder(options BuilderOptions) (Builder, error) {
 		options.GitHubClient.GetRepoOwner(),
 		options.GitHubClient.GetRepoName(),
 	)
+	changelog.SetRepoHost(options.GitHubClient.GetRepoHost())
 
 	builder := &builder{
 		nextVersion:   options.NextVersion,
 		fromVersion:   options.FromVersion,
 		latestVersion: options.LatestVersion,
+		sinceVersion:  options.SinceVersion,
+		remote:        options.Repo != "",
+		branch:        options.Branch,
+		fileName:      fileName,
 		changelog:     changelog,
 		git:           options.GitClient,
 		github:        options.GitHubClient,
+		firstMerges:   make(map[string]time.Time),
+	}
+
+	if options.Package != "" {
+		pkg, _ := configuration.Config.GetPackage(options.Package)
+		builder.tagPrefix = pkg.TagPrefix
+		builder.paths = pkg.Paths
+		builder.fromVersion = builder.withTagPrefix(builder.fromVersion)
+		builder.sinceVersion = builder.withTagPrefix(builder.sinceVersion)
+	}
+
+	if options.Line != "" {
+		builder.line, err = parseVersionLine(options.Line)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	loggerType, err := logging.GetLoggerType(options.Logger)
@@ -102,6 +225,21 @@ func (b *builder) BuildChangelog() (changelog.Changelog, error) {
 		return nil, err
 	}
 
+	if b.sinceVersion != "" && !b.hasTag(b.sinceVersion) {
+		err := fmt.Errorf("the version '%s' could not be found in the tags of this repository", b.sinceVersion)
+		b.logger.Errorf(err.Error())
+		return nil, err
+	}
+
+	if b.nextVersion == NextVersionAuto {
+		b.logger.Infof("Calculating next version...")
+		b.nextVersion, err = b.calculateNextVersion()
+		if err != nil {
+			b.logger.Errorf(err.Error())
+			return nil, err
+		}
+	}
+
 	if b.nextVersion != "" {
 		err = b.setNextVersion()
 		if err != nil {
@@ -111,24 +249,32 @@ func (b *builder) BuildChangelog() (changelog.Changelog, error) {
 	}
 
 	if configuration.Config.ShowUnreleased && b.nextVersion == "" {
-		b.logger.Infof("Getting unreleased entries")
-		err := b.getUnreleasedEntries()
+		headIsOnLine, err := b.headIsOnLine()
 		if err != nil {
 			return nil, err
 		}
+
+		if headIsOnLine {
+			b.logger.Infof("Getting unreleased entries")
+			err := b.getUnreleasedEntries()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			b.logger.Infof("Skipping unreleased entries because HEAD is not on the %s version line", b.line.name)
+		}
 	}
 
 	for i := 0; i < len(b.tags); i++ {
-		var previousTag githubclient.Tag
-		if i+1 == len(b.tags) {
-			previousTag = githubclient.Tag{}
-		} else {
-			previousTag = b.tags[i+1]
+		if strings.EqualFold(b.sinceVersion, b.tags[i].Name) {
+			break
 		}
 
-		err := b.getReleasedEntries(previousTag, b.tags[i])
-		if err != nil {
-			return nil, fmt.Errorf("could not process pull requests: %v", err)
+		if !b.isOmittedPrerelease(i) {
+			err := b.getReleasedEntries(b.getPreviousTag(i), b.tags[i])
+			if err != nil {
+				return nil, fmt.Errorf("could not process pull requests: %v", err)
+			}
 		}
 
 		if strings.EqualFold(b.fromVersion, b.tags[i].Name) || b.latestVersion {
@@ -136,14 +282,38 @@ func (b *builder) BuildChangelog() (changelog.Changelog, error) {
 		}
 	}
 
-	b.logger.Infof("Open %s or run 'gh changelog show' to view your changelog.", configuration.Config.FileName)
+	b.logger.Infof("Open %s or run 'gh changelog show' to view your changelog.", b.fileName)
 	b.logger.Complete()
 
 	return b.changelog, nil
 }
 
+// NextVersion calculates the next version from the entries that have not been
+// released yet.
+func (b *builder) NextVersion() (string, error) {
+	b.logger.Infof("Fetching tags...")
+	tags, err := b.getTags()
+	if err != nil {
+		b.logger.Errorf(err.Error())
+		return "", err
+	}
+
+	b.tags = tags
+
+	b.logger.Infof("Calculating next version...")
+	nextVersion, err := b.calculateNextVersion()
+	if err != nil {
+		b.logger.Errorf(err.Error())
+		return "", err
+	}
+
+	b.logger.Complete()
+
+	return nextVersion, nil
+}
+
 func (b *builder) updateTags() error {
-	tags, err := b.github.GetTags()
+	tags, err := b.getTags()
 	if err != nil {
 		return err
 	}
@@ -157,20 +327,377 @@ func (b *builder) updateTags() error {
 	return nil
 }
 
+// getTags returns the tags that belong in the changelog, latest first.
+func (b *builder) getTags() ([]githubclient.Tag, error) {
+	tags, err := b.getAllTags()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err = b.filterTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	switch configuration.Config.Tags.Sort {
+	case "", TagSortDate:
+		// Tags are already ordered by date, latest first.
+	case TagSortSemver:
+		b.sortTagsByVersion(tags)
+	default:
+		return nil, fmt.Errorf("'%s' is not a valid tag sort. Valid values are '%s' and '%s'", configuration.Config.Tags.Sort, TagSortDate, TagSortSemver)
+	}
+
+	return tags, nil
+}
+
+// filterTags removes the tags that do not belong in the changelog. These are
+// the tags of other packages, branches or version lines, tags that are not
+// matched by the include patterns or are matched by the exclude patterns and,
+// when semver_only is set, tags that are not semantic versions.
+func (b *builder) filterTags(tags []githubclient.Tag) ([]githubclient.Tag, error) {
+	reachable, err := b.getReachableTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []githubclient.Tag
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag.Name, b.tagPrefix) {
+			continue
+		}
+
+		if reachable != nil && !reachable[tag.Name] {
+			continue
+		}
+
+		if b.line != nil && !b.line.contains(strings.TrimPrefix(tag.Name, b.tagPrefix)) {
+			continue
+		}
+
+		included, err := includeTag(tag.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !included {
+			continue
+		}
+
+		if configuration.Config.Tags.SemverOnly && !utils.IsValidSemanticVersion(strings.TrimPrefix(tag.Name, b.tagPrefix)) {
+			continue
+		}
+
+		filtered = append(filtered, tag)
+	}
+
+	return filtered, nil
+}
+
+// headIsOnLine returns true if HEAD is on the version line, or if the
+// changelog is not for a version line without a branch. Changes after the
+// latest release of the line are read up to HEAD, which is only correct when
+// that release is reachable from HEAD and no newer release of another line is.
+func (b *builder) headIsOnLine() (bool, error) {
+	if b.line == nil || b.branch != "" || len(b.tags) == 0 {
+		return true, nil
+	}
+
+	names, err := b.git.GetTagsReachableFrom("HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	latest := strings.TrimPrefix(b.tags[0].Name, b.tagPrefix)
+	onLine := false
+	for _, name := range names {
+		if name == b.tags[0].Name {
+			onLine = true
+			continue
+		}
+
+		v := strings.TrimPrefix(name, b.tagPrefix)
+		if strings.HasPrefix(name, b.tagPrefix) && !b.line.contains(v) && utils.NextVersionIsGreaterThanCurrent(v, latest) {
+			return false, nil
+		}
+	}
+
+	return onLine, nil
+}
+
+// checkHeadIsOnLine returns an error if the next version of a version line
+// would be released from a HEAD that is not on the line.
+func (b *builder) checkHeadIsOnLine() error {
+	headIsOnLine, err := b.headIsOnLine()
+	if err != nil {
+		return err
+	}
+
+	if !headIsOnLine {
+		return fmt.Errorf("HEAD is not on the %s version line. Use --branch to choose the branch of the line", b.line.name)
+	}
+
+	return nil
+}
+
+// getReachableTags returns the names of the tags that are reachable from the
+// branch, or nil when the changelog is not for a branch.
+func (b *builder) getReachableTags() (map[string]bool, error) {
+	if b.branch == "" {
+		return nil, nil
+	}
+
+	names, err := b.git.GetTagsReachableFrom(b.branch)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := make(map[string]bool)
+	for _, name := range names {
+		reachable[name] = true
+	}
+
+	return reachable, nil
+}
+
+// includeTag returns true if the tag is matched by one of the include patterns,
+// or there are none, and is not matched by any of the exclude patterns.
+func includeTag(name string) (bool, error) {
+	include := configuration.Config.Tags.Include
+	exclude := configuration.Config.Tags.Exclude
+
+	included := len(include) == 0
+	for _, pattern := range include {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("'%s' is not a valid tag pattern: %v", pattern, err)
+		}
+
+		if matched {
+			included = true
+			break
+		}
+	}
+
+	if !included {
+		return false, nil
+	}
+
+	for _, pattern := range exclude {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("'%s' is not a valid tag pattern: %v", pattern, err)
+		}
+
+		if matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// sortTagsByVersion orders the tags by semantic version precedence, latest
+// first. Tags that are not semantic versions are placed after the others in
+// the order they were found.
+func (b *builder) sortTagsByVersion(tags []githubclient.Tag) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		left, err := version.NormalizeVersion(strings.TrimPrefix(tags[i].Name, b.tagPrefix))
+		if err != nil {
+			return false
+		}
+
+		right, err := version.NormalizeVersion(strings.TrimPrefix(tags[j].Name, b.tagPrefix))
+		if err != nil {
+			return true
+		}
+
+		return left.GreaterThan(right)
+	})
+}
+
+// getAllTags prefers tags from the local repository because reading them is much
+// faster than paging through the API. If the repository was cloned without
+// tags, or is not the local repository, the API is used instead.
+func (b *builder) getAllTags() ([]githubclient.Tag, error) {
+	if b.remote {
+		return b.github.GetTags()
+	}
+
+	localTags, err := b.git.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(localTags) == 0 {
+		return b.github.GetTags()
+	}
+
+	var tags []githubclient.Tag
+	for _, tag := range localTags {
+		tags = append(tags, githubclient.Tag{
+			Name: tag.Name,
+			Sha:  tag.Sha,
+			Date: tag.Date,
+		})
+	}
+
+	return tags, nil
+}
+
+// withTagPrefix adds the tag prefix of the package to the version if it does
+// not have it already.
+func (b *builder) withTagPrefix(version string) string {
+	if version == "" || strings.HasPrefix(version, b.tagPrefix) {
+		return version
+	}
+
+	return b.tagPrefix + version
+}
+
+// getPreviousTag returns the tag that the tag at the given index is compared
+// with. When pre-releases are folded, a stable version is compared with the tag
+// before its first pre-release so that the entry includes their changes.
+func (b *builder) getPreviousTag(i int) githubclient.Tag {
+	j := i + 1
+	if configuration.Config.Prereleases.Fold {
+		for j < len(b.tags) && b.isPrereleaseOf(b.tags[j].Name, b.tags[i].Name) {
+			j++
+		}
+	}
+
+	if j == len(b.tags) {
+		return githubclient.Tag{}
+	}
+
+	return b.tags[j]
+}
+
+// isOmittedPrerelease returns true if the tag at the given index is a
+// pre-release that has been folded into a newer stable version and should not
+// have an entry of its own.
+func (b *builder) isOmittedPrerelease(i int) bool {
+	prereleases := configuration.Config.Prereleases
+	if !prereleases.Fold || prereleases.KeepEntries {
+		return false
+	}
+
+	for _, tag := range b.tags[:i] {
+		if b.isPrereleaseOf(b.tags[i].Name, tag.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *builder) isPrereleaseOf(tag, stable string) bool {
+	return version.IsPrereleaseOf(strings.TrimPrefix(tag, b.tagPrefix), strings.TrimPrefix(stable, b.tagPrefix))
+}
+
+// versionLine is a major or minor version line, such as 1.x or 1.4.x.
+type versionLine struct {
+	name     string
+	major    uint64
+	minor    uint64
+	hasMinor bool
+}
+
+// parseVersionLine parses a version line in the MAJOR[.MINOR][.x] format. A
+// leading v is ignored.
+func parseVersionLine(line string) (*versionLine, error) {
+	invalid := fmt.Errorf("'%s' is not a valid version line. Use a major or minor version such as 1.x or 1.4.x", line)
+
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(line, "v"), ".x")
+	parts := strings.Split(trimmed, ".")
+	if len(parts) > 2 {
+		return nil, invalid
+	}
+
+	major, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+
+	l := &versionLine{name: line, major: major}
+	if len(parts) == 2 {
+		l.minor, err = strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+
+		l.hasMinor = true
+	}
+
+	return l, nil
+}
+
+// contains returns true if the version belongs to the line. Versions that are
+// not semantic versions do not belong to any line.
+func (l *versionLine) contains(v string) bool {
+	sv, err := version.NormalizeVersion(v)
+	if err != nil {
+		return false
+	}
+
+	return sv.Major() == l.major && (!l.hasMinor || sv.Minor() == l.minor)
+}
+
+// limitIncrement returns the largest increment, up to the given one, that
+// keeps the next version in the line.
+func (l *versionLine) limitIncrement(increment string) string {
+	switch {
+	case l.hasMinor:
+		return "patch"
+	case increment == "major":
+		return "minor"
+	default:
+		return increment
+	}
+}
+
+func (b *builder) hasTag(name string) bool {
+	for _, tag := range b.tags {
+		if strings.EqualFold(tag.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (b *builder) setNextVersion() error {
-	if !utils.IsValidSemanticVersion(b.nextVersion) {
+	b.nextVersion = b.withTagPrefix(b.nextVersion)
+	nextVersion := strings.TrimPrefix(b.nextVersion, b.tagPrefix)
+
+	if !utils.IsValidSemanticVersion(nextVersion) {
 		return fmt.Errorf("'%s' is not a valid semantic version", b.nextVersion)
 	}
+
+	if b.line != nil && !b.line.contains(nextVersion) {
+		return fmt.Errorf("'%s' is not in the %s version line", b.nextVersion, b.line.name)
+	}
+
+	if err := b.checkHeadIsOnLine(); err != nil {
+		return err
+	}
 	if len(b.tags) > 0 {
 		currentVersion := b.tags[0].Name
-		if !utils.NextVersionIsGreaterThanCurrent(b.nextVersion, currentVersion) {
+		if !utils.NextVersionIsGreaterThanCurrent(nextVersion, strings.TrimPrefix(currentVersion, b.tagPrefix)) {
 			return fmt.Errorf("the next version should be greater than the former: '%s' ≤ '%s'", b.nextVersion, currentVersion)
 		}
 	}
 
-	lastCommitSha, err := b.git.GetLastCommit()
-	if err != nil {
-		return err
+	// The sha is only used with the commit graph, which needs a local clone.
+	var lastCommitSha string
+	if b.branch != "" {
+		lastCommitSha = b.branch
+	} else if !b.remote {
+		var err error
+		lastCommitSha, err = b.git.GetLastCommit()
+		if err != nil {
+			return err
+		}
 	}
 
 	tag := githubclient.Tag{
@@ -184,18 +711,68 @@ func (b *builder) setNextVersion() error {
 	return nil
 }
 
+// calculateNextVersion increments the latest tag based on the sections of the
+// unreleased entries. The increment for each section is taken from the
+// version_increments configuration and defaults to patch. On a version line
+// the increment is limited so that the next version stays in the line.
+func (b *builder) calculateNextVersion() (string, error) {
+	if len(b.tags) == 0 {
+		return "", errors.New("there are no tags on this repository to calculate the next version from")
+	}
+
+	if err := b.checkHeadIsOnLine(); err != nil {
+		return "", err
+	}
+
+	pullRequests, err := b.getPullRequests(b.tags[0], b.headTag())
+	if err != nil {
+		return "", err
+	}
+
+	rank := map[string]int{"patch": 0, "minor": 1, "major": 2}
+	increment := "patch"
+	for _, pr := range pullRequests {
+		section := strings.ToLower(getSection(pr))
+		if i, ok := configuration.Config.VersionIncrements[section]; ok && rank[i] > rank[increment] {
+			increment = i
+		}
+	}
+
+	if b.line != nil {
+		increment = b.line.limitIncrement(increment)
+	}
+
+	nextVersion, err := utils.IncrementVersion(strings.TrimPrefix(b.tags[0].Name, b.tagPrefix), increment)
+	if err != nil {
+		return "", err
+	}
+
+	return b.tagPrefix + nextVersion, nil
+}
+
 func (b *builder) getUnreleasedEntries() error {
-	pullRequests, err := b.github.GetPullRequestsBetweenDates(b.tags[0].Date, Now())
+	all, err := b.getPullRequestsBetweenTags(b.tags[0], b.headTag())
+	if err != nil {
+		return err
+	}
+
+	pullRequests, err := b.filterPullRequests(all)
 	if err != nil {
 		return err
 	}
 
-	unreleased := []string{}
+	unreleased := []entry.Item{}
 	for _, pr := range pullRequests {
-		if !hasExcludedLabel(pr) {
-			line := b.formatEntryLine(pr)
-			unreleased = append(unreleased, line)
+		unreleased = append(unreleased, newItem(pr))
+	}
+
+	if configuration.Config.DirectCommits.Enabled {
+		items, err := b.getDirectCommits(b.tags[0], b.headTag(), all)
+		if err != nil {
+			return err
 		}
+
+		unreleased = append(unreleased, items...)
 	}
 
 	b.changelog.AddUnreleased(unreleased)
@@ -206,41 +783,435 @@ func (b *builder) getUnreleasedEntries() error {
 func (b *builder) getReleasedEntries(previousTag, currentTag githubclient.Tag) error {
 	b.logger.Infof("Processing tag: 🏷️  %s", currentTag.Name)
 
-	pullRequests, err := b.github.GetPullRequestsBetweenDates(previousTag.Date, currentTag.Date)
+	all, err := b.getPullRequestsBetweenTags(previousTag, currentTag)
+	if err != nil {
+		return err
+	}
+
+	pullRequests, err := b.filterPullRequests(all)
 	if err != nil {
 		return err
 	}
 
 	e := entry.NewEntry(currentTag.Name, currentTag.Date)
+	e.PrevTag = previousTag.Name
 
+	// The writer compares the oldest entry with the first commit of the local
+	// repository, which is the wrong repository in this case.
+	if e.PrevTag == "" && b.remote {
+		e.PrevTag, err = b.getFirstCommit()
+		if err != nil {
+			return err
+		}
+	}
+
+	var included []githubclient.PullRequest
 	for _, pr := range pullRequests {
-		if !hasExcludedLabel(pr) {
-			section := getSection(pr.Labels)
-			line := b.formatEntryLine(pr)
-
-			if section != "" {
-				err := e.Append(section, line)
-				if err != nil {
-					return err
-				}
+		section := getSection(pr)
+		item := newItem(pr)
+
+		if section != "" {
+			err := e.Append(getSectionTitle(section), item)
+			if err != nil {
+				return err
+			}
+
+			included = append(included, pr)
+		}
+	}
+
+	if configuration.Config.DirectCommits.Enabled {
+		items, err := b.getDirectCommits(previousTag, currentTag, all)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			err := e.Append(getSectionTitle(configuration.Config.DirectCommits.Section), item)
+			if err != nil {
+				return err
 			}
 		}
 	}
+
+	e.SortSections(configuration.Config.SectionOrder)
+
+	if configuration.Config.Contributors.Enabled {
+		e.Contributors, err = b.getContributors(included, previousTag)
+		if err != nil {
+			return err
+		}
+	}
+
 	b.changelog.Insert(e)
 	return nil
 }
 
-func (b *builder) formatEntryLine(pr githubclient.PullRequest) string {
-	return fmt.Sprintf(
-		"%s [#%d](https://github.com/%s/%s/pull/%d) ([%s](https://github.com/%s))",
-		pr.Title,
-		pr.Number,
-		b.github.GetRepoOwner(),
-		b.github.GetRepoName(),
-		pr.Number,
-		pr.User,
-		pr.User,
-	)
+func (b *builder) getFirstCommit() (string, error) {
+	if b.firstCommit == "" {
+		firstCommit, err := b.github.GetFirstCommit()
+		if err != nil {
+			return "", err
+		}
+
+		b.firstCommit = firstCommit
+	}
+
+	return b.firstCommit, nil
+}
+
+// headTag returns a tag that represents the current HEAD of the repository,
+// or the tip of the branch when the changelog is for a branch.
+func (b *builder) headTag() githubclient.Tag {
+	ref := "HEAD"
+	if b.branch != "" {
+		ref = b.branch
+	}
+
+	return githubclient.Tag{
+		Name: ref,
+		Sha:  ref,
+		Date: Now(),
+	}
+}
+
+// getPullRequests returns the pull requests that belong between the two tags,
+// without the pull requests that are left out by filterPullRequests.
+func (b *builder) getPullRequests(previousTag, currentTag githubclient.Tag) ([]githubclient.PullRequest, error) {
+	pullRequests, err := b.getPullRequestsBetweenTags(previousTag, currentTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.filterPullRequests(pullRequests)
+}
+
+// filterPullRequests leaves out the pull requests that are excluded by their
+// labels, author or title. When the changelog is for a package with paths,
+// only the pull requests that changed a file under those paths are kept.
+func (b *builder) filterPullRequests(pullRequests []githubclient.PullRequest) ([]githubclient.PullRequest, error) {
+	var filtered []githubclient.PullRequest
+	for _, pr := range pullRequests {
+		excluded, err := isExcluded(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		if excluded {
+			continue
+		}
+
+		if len(b.paths) > 0 {
+			files, err := b.getChangedFiles(pr)
+			if err != nil {
+				return nil, err
+			}
+
+			if !b.touchesPaths(files) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, pr)
+	}
+
+	return filtered, nil
+}
+
+// getDirectCommits returns an item for each commit between the two tags that
+// was pushed directly instead of being merged with a pull request. Commits are
+// matched with the merge commits of the given pull requests, which should
+// include the pull requests that are excluded from the changelog, and squash
+// merges are recognised by the (#123) suffix that GitHub adds to them. A pull
+// request that was merged with a rebase adds each of its commits to the
+// branch, so they are also matched with the subjects of its commits.
+func (b *builder) getDirectCommits(previousTag, currentTag githubclient.Tag, pullRequests []githubclient.PullRequest) ([]entry.Item, error) {
+	commits, err := b.git.GetFirstParentCommitsBetween(previousTag.Sha, currentTag.Sha)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]bool)
+	rebased := make(map[string]bool)
+	for _, pr := range pullRequests {
+		merged[pr.MergeCommitSha] = true
+		for _, subject := range pr.CommitSubjects {
+			rebased[subject] = true
+		}
+	}
+
+	var items []entry.Item
+	for i, commit := range commits {
+		if merged[commit.Sha] || rebased[commit.Subject] || pullRequestSubjectRegex.MatchString(commit.Subject) {
+			continue
+		}
+
+		excluded, err := hasExcludedTitle(commit.Subject)
+		if err != nil {
+			return nil, err
+		}
+
+		if excluded {
+			continue
+		}
+
+		if len(b.paths) > 0 {
+			// The first commit of the repository does not have a parent to
+			// find the changed files with.
+			if previousTag.Sha == "" && i == len(commits)-1 {
+				continue
+			}
+
+			files, err := b.git.GetChangedFiles(commit.Sha)
+			if err != nil {
+				return nil, err
+			}
+
+			if !b.touchesPaths(files) {
+				continue
+			}
+		}
+
+		items = append(items, entry.Item{
+			Title:    commit.Subject,
+			MergeSha: commit.Sha,
+			MergedAt: commit.Date,
+		})
+	}
+
+	return items, nil
+}
+
+// getChangedFiles returns the files that were changed by the pull request.
+// They are read from the merge commit in the local repository, or from GitHub
+// when the changelog is for another repository.
+func (b *builder) getChangedFiles(pr githubclient.PullRequest) ([]string, error) {
+	if b.remote {
+		return b.github.GetChangedFiles(pr.Number)
+	}
+
+	if pr.MergeCommitSha == "" {
+		return nil, nil
+	}
+
+	return b.git.GetChangedFiles(pr.MergeCommitSha)
+}
+
+func (b *builder) touchesPaths(files []string) bool {
+	for _, file := range files {
+		for _, pattern := range b.paths {
+			if utils.MatchPath(pattern, file) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// getPullRequestsBetweenTags returns the pull requests that belong between the two tags.
+// By default a pull request belongs to a tag if it was merged between the
+// dates of the two tags. When use_commit_graph is enabled, only pull requests
+// whose merge commit is reachable from the current tag but not from the
+// previous tag are returned. The commit graph is always used for a branch or
+// version line of the local repository.
+func (b *builder) getPullRequestsBetweenTags(previousTag, currentTag githubclient.Tag) ([]githubclient.PullRequest, error) {
+	if !b.useCommitGraph() {
+		return b.github.GetPullRequestsBetweenDates(previousTag.Date, currentTag.Date)
+	}
+
+	commits, err := b.git.GetCommitsBetween(previousTag.Sha, currentTag.Sha)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	shas := make(map[string]bool)
+	from, to := commits[0].Date, commits[0].Date
+	for _, commit := range commits {
+		shas[commit.Sha] = true
+		if commit.Date.Before(from) {
+			from = commit.Date
+		}
+		if commit.Date.After(to) {
+			to = commit.Date
+		}
+	}
+
+	// Only the window covered by the commits in the range needs to be searched.
+	pullRequests, err := b.github.GetPullRequestsBetweenDates(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []githubclient.PullRequest
+	for _, pr := range pullRequests {
+		if shas[pr.MergeCommitSha] {
+			filtered = append(filtered, pr)
+		}
+	}
+
+	return filtered, nil
+}
+
+// useCommitGraph returns true if pull requests are assigned to releases with
+// the commit graph. Releases on a branch or version line are interleaved with
+// the releases of other lines, so their dates can not be used.
+func (b *builder) useCommitGraph() bool {
+	return configuration.Config.UseCommitGraph || b.line != nil || b.branch != ""
+}
+
+// getContributors returns the authors of the pull requests in a release,
+// sorted by login. An author is a first-time contributor when none of their
+// pull requests were merged before the previous release.
+func (b *builder) getContributors(pullRequests []githubclient.PullRequest, previousTag githubclient.Tag) ([]entry.Contributor, error) {
+	seen := make(map[string]bool)
+	var contributors []entry.Contributor
+	for _, pr := range pullRequests {
+		login := strings.ToLower(pr.User)
+		if pr.User == "" || seen[login] || isExcludedContributor(pr) {
+			continue
+		}
+
+		seen[login] = true
+
+		// Everyone is a first-time contributor to the first release.
+		firstTime := true
+		if !previousTag.Date.IsZero() {
+			firstMerge, err := b.getFirstMergeDate(pr.User)
+			if err != nil {
+				return nil, err
+			}
+
+			firstTime = firstMerge.IsZero() || !firstMerge.Before(previousTag.Date)
+		}
+
+		contributors = append(contributors, entry.Contributor{Login: pr.User, FirstTime: firstTime})
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return strings.ToLower(contributors[i].Login) < strings.ToLower(contributors[j].Login)
+	})
+
+	return contributors, nil
+}
+
+// getFirstMergeDate returns the date that the first pull request by the user
+// was merged. Dates are cached so that each author is only looked up once for
+// the whole changelog.
+func (b *builder) getFirstMergeDate(user string) (time.Time, error) {
+	login := strings.ToLower(user)
+	if date, ok := b.firstMerges[login]; ok {
+		return date, nil
+	}
+
+	date, err := b.github.GetFirstMergeDate(user)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	b.firstMerges[login] = date
+
+	return date, nil
+}
+
+func isExcludedContributor(pr githubclient.PullRequest) bool {
+	config := configuration.Config.Contributors
+	if config.ExcludeBots && (pr.IsBot || strings.HasSuffix(pr.User, "[bot]")) {
+		return true
+	}
+
+	for _, user := range getLogins(pr) {
+		for _, login := range config.Exclude {
+			if strings.EqualFold(login, user) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// getLogins returns the logins that the author of a pull request is matched
+// with. The GitHub API returns the logins of bots without the [bot] suffix, so
+// it is added for them.
+func getLogins(pr githubclient.PullRequest) []string {
+	logins := []string{pr.User}
+	if pr.IsBot && !strings.HasSuffix(pr.User, "[bot]") {
+		logins = append(logins, pr.User+"[bot]")
+	}
+
+	return logins
+}
+
+// newItem creates a changelog item from a pull request. Rendering the item is
+// left to the writer.
+func newItem(pr githubclient.PullRequest) entry.Item {
+	var labels []string
+	for _, label := range pr.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	return entry.Item{
+		Title:    getTitle(pr),
+		Number:   pr.Number,
+		Author:   pr.User,
+		URL:      pr.URL,
+		Labels:   labels,
+		MergeSha: pr.MergeCommitSha,
+		MergedAt: pr.MergedAt,
+	}
+}
+
+// isExcluded returns true if the pull request is left out of the changelog
+// because of its labels, author or title.
+func isExcluded(pr githubclient.PullRequest) (bool, error) {
+	if hasExcludedLabel(pr) || !hasIncludedAuthor(pr) || hasExcludedAuthor(pr) {
+		return true, nil
+	}
+
+	return hasExcludedTitle(pr.Title)
+}
+
+// hasIncludedAuthor returns true if the author of the pull request matches one
+// of the included_authors patterns, or there are none.
+func hasIncludedAuthor(pr githubclient.PullRequest) bool {
+	included := configuration.Config.IncludedAuthors
+	return len(included) == 0 || matchesAuthor(pr, included)
+}
+
+func hasExcludedAuthor(pr githubclient.PullRequest) bool {
+	return matchesAuthor(pr, configuration.Config.ExcludedAuthors)
+}
+
+func matchesAuthor(pr githubclient.PullRequest, patterns []string) bool {
+	for _, login := range getLogins(pr) {
+		for _, pattern := range patterns {
+			if utils.MatchLogin(pattern, login) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasExcludedTitle(title string) (bool, error) {
+	for _, pattern := range configuration.Config.ExcludedTitlePatterns {
+		matched, err := regexp.MatchString(pattern, title)
+		if err != nil {
+			return false, fmt.Errorf("'%s' is not a valid title pattern: %v", pattern, err)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 func hasExcludedLabel(pr githubclient.PullRequest) bool {
@@ -254,7 +1225,33 @@ func hasExcludedLabel(pr githubclient.PullRequest) bool {
 	return false
 }
 
-func getSection(labels []githubclient.PullRequestLabel) string {
+// getSection returns the section that the pull request belongs to. Sections
+// are determined from labels and, when conventional commits are enabled, from
+// the title of the pull request. The precedence setting decides which one
+// wins when both match, but a breaking change always belongs in changed.
+func getSection(pr githubclient.PullRequest) string {
+	section := getSectionFromLabels(pr.Labels)
+
+	conventionalCommits := configuration.Config.ConventionalCommits
+	if conventionalCommits.Enabled {
+		if isBreakingChange(pr) {
+			return "changed"
+		}
+
+		titleSection := getSectionFromTitle(pr)
+		if titleSection != "" && (section == "" || conventionalCommits.Precedence == "title") {
+			section = titleSection
+		}
+	}
+
+	if section == "" && !configuration.Config.SkipEntriesWithoutLabel {
+		section = "Other"
+	}
+
+	return section
+}
+
+func getSectionFromLabels(labels []githubclient.PullRequestLabel) string {
 	sections := configuration.Config.Sections
 
 	lookup := make(map[string]string)
@@ -265,12 +1262,6 @@ func getSection(labels []githubclient.PullRequestLabel) string {
 	}
 
 	var section string
-	skipUnlabelledEntries := configuration.Config.SkipEntriesWithoutLabel
-
-	if !skipUnlabelledEntries {
-		section = "Other"
-	}
-
 	for _, label := range labels {
 		if _, ok := lookup[label.Name]; ok {
 			section = lookup[label.Name]
@@ -279,3 +1270,41 @@ func getSection(labels []githubclient.PullRequestLabel) string {
 
 	return section
 }
+
+func getSectionFromTitle(pr githubclient.PullRequest) string {
+	title, ok := conventional.Parse(pr.Title)
+	if !ok {
+		return ""
+	}
+
+	return configuration.Config.ConventionalCommits.Types[title.Type]
+}
+
+// isBreakingChange returns true if the title of the pull request is marked
+// with ! or the pull request has a BREAKING CHANGE footer.
+func isBreakingChange(pr githubclient.PullRequest) bool {
+	title, _ := conventional.Parse(pr.Title)
+	return title.Breaking || conventional.HasBreakingChange(pr.Title) || conventional.HasBreakingChange(pr.Body)
+}
+
+// getSectionTitle converts a configured section name such as
+// breaking_changes in to the title that is rendered in the changelog.
+func getSectionTitle(section string) string {
+	title := cases.Title(language.English)
+	return title.String(strings.ReplaceAll(section, "_", " "))
+}
+
+// getTitle returns the title of the pull request. When conventional commits
+// are enabled, the type and scope prefix is removed.
+func getTitle(pr githubclient.PullRequest) string {
+	if !configuration.Config.ConventionalCommits.Enabled {
+		return pr.Title
+	}
+
+	title, ok := conventional.Parse(pr.Title)
+	if !ok {
+		return pr.Title
+	}
+
+	return title.Description
+}
@@ -1,14 +1,18 @@
 package builder_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/chelnak/gh-changelog/internal/configuration"
+	"github.com/chelnak/gh-changelog/internal/gitclient"
 	"github.com/chelnak/gh-changelog/internal/githubclient"
 	"github.com/chelnak/gh-changelog/mocks"
 	"github.com/chelnak/gh-changelog/pkg/builder"
+	"github.com/chelnak/gh-changelog/pkg/entry"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 const (
@@ -26,6 +30,7 @@ func setupMockGitClient() *mocks.GitClient {
 	mockGitClient.On("GetFirstCommit").Return("42d4c93b23eaf307c5f9712f4c62014fe38332bd", nil)
 	mockGitClient.On("GetLastCommit").Return("0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", nil)
 	mockGitClient.On("GetDateOfHash", "42d4c93b23eaf307c5f9712f4c62014fe38332bd").Return(safeParseTime(), nil).Once()
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{}, nil)
 	return mockGitClient
 }
 
@@ -75,6 +80,7 @@ func setupMockGitHubClient() *mocks.GitHubClient {
 
 	mockGitHubClient.On("GetRepoName").Return(repoName)
 	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
 
 	return mockGitHubClient
 }
@@ -107,15 +113,15 @@ func TestChangelogBuilder(t *testing.T) {
 
 	assert.Equal(t, repoName, changelog.GetRepoName())
 	assert.Equal(t, repoOwner, changelog.GetRepoOwner())
+	assert.Equal(t, "github.com", changelog.GetRepoHost())
 
 	assert.Len(t, changelog.GetUnreleased(), 0)
 	assert.Len(t, changelog.GetEntries(), 2)
 
-	assert.Equal(
-		t,
-		"this is a test pr 2 [#2](https://github.com/repo-owner/repo-name/pull/2) ([test-user](https://github.com/test-user))",
-		changelog.GetEntries()[0].Added[0],
-	)
+	item := changelog.GetEntries()[0].GetSection("added")[0]
+	assert.Equal(t, "this is a test pr 2", item.Title)
+	assert.Equal(t, 2, item.Number)
+	assert.Equal(t, "test-user", item.Author)
 }
 
 func TestShouldErrorWithAnOlderNextVersion(t *testing.T) {
@@ -134,6 +140,7 @@ func TestShouldErrorWithNoTags(t *testing.T) {
 	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{}, nil)
 	mockGitHubClient.On("GetRepoName").Return(repoName)
 	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
 
 	opts := &builder.BuilderOptions{
 		GitHubClient: mockGitHubClient,
@@ -156,11 +163,10 @@ func TestWithFromVersion(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Len(t, changelog.GetEntries(), 1)
-	assert.Equal(
-		t,
-		"this is a test pr 2 [#2](https://github.com/repo-owner/repo-name/pull/2) ([test-user](https://github.com/test-user))",
-		changelog.GetEntries()[0].Added[0],
-	)
+	item := changelog.GetEntries()[0].GetSection("added")[0]
+	assert.Equal(t, "this is a test pr 2", item.Title)
+	assert.Equal(t, 2, item.Number)
+	assert.Equal(t, "test-user", item.Author)
 }
 
 func TestWithFromLastVersion(t *testing.T) {
@@ -173,9 +179,1283 @@ func TestWithFromLastVersion(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Len(t, changelog.GetEntries(), 1)
-	assert.Equal(
-		t,
-		"this is a test pr 2 [#2](https://github.com/repo-owner/repo-name/pull/2) ([test-user](https://github.com/test-user))",
-		changelog.GetEntries()[0].Added[0],
-	)
+	item := changelog.GetEntries()[0].GetSection("added")[0]
+	assert.Equal(t, "this is a test pr 2", item.Title)
+	assert.Equal(t, 2, item.Number)
+	assert.Equal(t, "test-user", item.Author)
+}
+
+func TestWithSinceVersion(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		SinceVersion: "v1.0.0",
+	}
+
+	builder := setupBuilder(opts)
+	changelog, err := builder.BuildChangelog()
+
+	assert.NoError(t, err)
+	assert.Len(t, changelog.GetEntries(), 1)
+	assert.Equal(t, "v2.0.0", changelog.GetEntries()[0].Tag)
+	assert.Equal(t, "v1.0.0", changelog.GetEntries()[0].PrevTag)
+}
+
+func TestWithSinceTheLatestVersion(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		SinceVersion: "v2.0.0",
+	}
+
+	builder := setupBuilder(opts)
+	changelog, err := builder.BuildChangelog()
+
+	assert.NoError(t, err)
+	assert.Len(t, changelog.GetEntries(), 0)
+}
+
+func TestShouldErrorWithAnUnknownSinceVersion(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		SinceVersion: "v3.0.0",
+	}
+
+	builder := setupBuilder(opts)
+	_, err := builder.BuildChangelog()
+
+	assert.Error(t, err)
+	assert.Equal(t, "the version 'v3.0.0' could not be found in the tags of this repository", err.Error())
+}
+
+func TestPrefersLocalTags(t *testing.T) {
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{
+		{
+			Name: "v2.0.0",
+			Sha:  "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1",
+			Date: safeParseTime(),
+		},
+	}, nil)
+
+	mockGitHubClient := setupMockGitHubClient()
+
+	opts := &builder.BuilderOptions{
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	}
+
+	builder := setupBuilder(opts)
+	changelog, err := builder.BuildChangelog()
+
+	assert.NoError(t, err)
+	assert.Len(t, changelog.GetEntries(), 1)
+	assert.Equal(t, "v2.0.0", changelog.GetEntries()[0].Tag)
+	mockGitHubClient.AssertNotCalled(t, "GetTags")
+}
+
+func TestWithCommitGraph(t *testing.T) {
+	defer func() { configuration.Config.UseCommitGraph = false }()
+
+	first := time.Date(2022, 4, 17, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 4, 18, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{
+		{Name: "v2.0.0", Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: second},
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitClient.On("GetCommitsBetween", "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", "HEAD").Return([]gitclient.Commit{}, nil)
+	mockGitClient.On("GetCommitsBetween", "42d4c93b23eaf307c5f9712f4c62014fe38332bd", "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1").Return([]gitclient.Commit{
+		{Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: second},
+	}, nil)
+	mockGitClient.On("GetCommitsBetween", "", "42d4c93b23eaf307c5f9712f4c62014fe38332bd").Return([]gitclient.Commit{
+		{Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+	mockGitHubClient.On("GetPullRequestsBetweenDates", second, second).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "merged on a release branch", User: "test-user", MergeCommitSha: "ffffffffffffffffffffffffffffffffffffffff"},
+		{Number: 2, Title: "this is a test pr 2", User: "test-user", MergeCommitSha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, first).Return([]githubclient.PullRequest{
+		{Number: 1, Title: "this is a test pr", User: "test-user", MergeCommitSha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd"},
+	}, nil)
+
+	opts := &builder.BuilderOptions{
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	}
+
+	builder := setupBuilder(opts)
+	configuration.Config.UseCommitGraph = true
+
+	changelog, err := builder.BuildChangelog()
+
+	assert.NoError(t, err)
+	assert.Len(t, changelog.GetEntries(), 2)
+	assert.Len(t, changelog.GetEntries()[0].GetSection("other"), 1)
+	item := changelog.GetEntries()[0].GetSection("other")[0]
+	assert.Equal(t, "this is a test pr 2", item.Title)
+	assert.Equal(t, 2, item.Number)
+	assert.Equal(t, "test-user", item.Author)
+	assert.Len(t, changelog.GetEntries()[1].GetSection("other"), 1)
+}
+
+func TestWithConventionalCommits(t *testing.T) {
+	defer func() { configuration.Config.ConventionalCommits.Enabled = false }()
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{
+			Name: "v1.0.0",
+			Sha:  "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Date: safeParseTime(),
+		},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, time.Time{}).Return([]githubclient.PullRequest{
+		{Number: 4, Title: "feat(api): add a new endpoint", User: "test-user"},
+		{Number: 3, Title: "fix!: remove the old endpoint", User: "test-user"},
+		{Number: 2, Title: "fix: correct a typo", User: "test-user", Body: "BREAKING CHANGE: the typo was load bearing"},
+		{Number: 1, Title: "feat: labelled as a bug", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	builder := setupBuilder(opts)
+	configuration.Config.ConventionalCommits.Enabled = true
+
+	changelog, err := builder.BuildChangelog()
+	assert.NoError(t, err)
+
+	e := changelog.GetEntries()[0]
+	assert.Len(t, e.GetSection("added"), 1)
+	assert.Equal(t, "add a new endpoint", e.GetSection("added")[0].Title)
+	assert.Len(t, e.GetSection("changed"), 2)
+	assert.Equal(t, "remove the old endpoint", e.GetSection("changed")[0].Title)
+	assert.Equal(t, "correct a typo", e.GetSection("changed")[1].Title)
+	assert.Len(t, e.GetSection("fixed"), 1)
+	assert.Equal(t, "labelled as a bug", e.GetSection("fixed")[0].Title)
+	assert.Len(t, e.GetSection("other"), 0)
+}
+
+func TestWithConventionalCommitsTitlePrecedence(t *testing.T) {
+	defer func() {
+		configuration.Config.ConventionalCommits.Enabled = false
+		configuration.Config.ConventionalCommits.Precedence = "labels"
+	}()
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{
+			Name: "v1.0.0",
+			Sha:  "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Date: safeParseTime(),
+		},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, time.Time{}).Return([]githubclient.PullRequest{
+		{Number: 1, Title: "feat: labelled as a bug", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	builder := setupBuilder(opts)
+	configuration.Config.ConventionalCommits.Enabled = true
+	configuration.Config.ConventionalCommits.Precedence = "title"
+
+	changelog, err := builder.BuildChangelog()
+	assert.NoError(t, err)
+
+	e := changelog.GetEntries()[0]
+	assert.Len(t, e.GetSection("added"), 1)
+	assert.Len(t, e.GetSection("fixed"), 0)
+}
+
+func TestWithConventionalCommitsBreakingChanges(t *testing.T) {
+	defer func() { configuration.Config.ConventionalCommits.Enabled = false }()
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{
+			Name: "v1.0.0",
+			Sha:  "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Date: safeParseTime(),
+		},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, time.Time{}).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "fix!: remove the old endpoint", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}},
+		{Number: 2, Title: "fix: correct a typo", User: "test-user", Body: "Some description\n\nBREAKING-CHANGE: the typo was load bearing", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}},
+		{Number: 1, Title: "fix: update the docs", User: "test-user", Body: "## Checklist\n\n- [ ] BREAKING CHANGE\n- [x] Tests added"},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.ConventionalCommits.Enabled = true
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	// Breaking changes belong in changed even when a label matches another section.
+	e := changelog.GetEntries()[0]
+	assert.Equal(t, []int{3, 2}, getItemNumbers(e.GetSection("changed")))
+	assert.Equal(t, []int{1}, getItemNumbers(e.GetSection("fixed")))
+}
+
+func setupMockGitHubClientWithLabel(label string) *mocks.GitHubClient {
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{
+			Name: "v1.2.3",
+			Sha:  "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Date: safeParseTime(),
+		},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, time.Time{}).Return([]githubclient.PullRequest{
+		{Number: 2, Title: "this is a test pr 2", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}},
+		{Number: 1, Title: "this is a test pr", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: label}}},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	return mockGitHubClient
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{label: "backwards-incompatible", want: "v2.0.0"},
+		{label: "enhancement", want: "v1.3.0"},
+		{label: "bug", want: "v1.2.4"},
+		{label: "maintenance", want: "v1.2.4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.label, func(t *testing.T) {
+			opts := &builder.BuilderOptions{
+				GitHubClient: setupMockGitHubClientWithLabel(test.label),
+			}
+
+			builder := setupBuilder(opts)
+			nextVersion, err := builder.NextVersion()
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, nextVersion)
+		})
+	}
+}
+
+func TestWithAutoNextVersion(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		NextVersion:  builder.NextVersionAuto,
+		GitHubClient: setupMockGitHubClientWithLabel("enhancement"),
+	}
+
+	builder := setupBuilder(opts)
+	changelog, err := builder.BuildChangelog()
+
+	assert.NoError(t, err)
+	assert.Len(t, changelog.GetEntries(), 2)
+	assert.Equal(t, "v1.3.0", changelog.GetEntries()[0].Tag)
+}
+
+func TestNextVersionWithConventionalCommits(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "perf: cache the tags", want: "v1.2.4"},
+		{title: "refactor: split the builder", want: "v1.2.4"},
+		{title: "feat: add a flag", want: "v1.3.0"},
+		{title: "refactor!: remove a flag", want: "v2.0.0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			mockGitHubClient := &mocks.GitHubClient{}
+			mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+				{Name: "v1.2.3", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: safeParseTime()},
+			}, nil)
+			mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, time.Time{}).Return([]githubclient.PullRequest{
+				{Number: 2, Title: "fix: correct a typo", User: "test-user"},
+				{Number: 1, Title: test.title, User: "test-user"},
+			}, nil)
+			mockGitHubClient.On("GetRepoName").Return(repoName)
+			mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+			mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+			opts := &builder.BuilderOptions{
+				GitHubClient: mockGitHubClient,
+			}
+
+			b := setupBuilder(opts)
+			configuration.Config.ConventionalCommits.Enabled = true
+			defer func() { configuration.Config.ConventionalCommits.Enabled = false }()
+
+			nextVersion, err := b.NextVersion()
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, nextVersion)
+		})
+	}
+}
+
+func TestWithCustomSections(t *testing.T) {
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{
+			Name: "v1.0.0",
+			Sha:  "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Date: safeParseTime(),
+		},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, time.Time{}).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "a bug fix", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}},
+		{Number: 2, Title: "a faster parser", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "performance"}}},
+		{Number: 1, Title: "a breaking change", User: "test-user", Labels: []githubclient.PullRequestLabel{{Name: "breaking"}}},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	builder := setupBuilder(opts)
+	configuration.Config.Sections["performance"] = []string{"performance"}
+	configuration.Config.Sections["breaking_changes"] = []string{"breaking"}
+	configuration.Config.SectionOrder = []string{"breaking_changes", "fixed", "performance"}
+
+	defer func() {
+		delete(configuration.Config.Sections, "performance")
+		delete(configuration.Config.Sections, "breaking_changes")
+	}()
+
+	changelog, err := builder.BuildChangelog()
+	assert.NoError(t, err)
+
+	e := changelog.GetEntries()[0]
+	assert.Len(t, e.Sections, 3)
+	assert.Equal(t, "Breaking Changes", e.Sections[0].Name)
+	assert.Equal(t, "Fixed", e.Sections[1].Name)
+	assert.Equal(t, "Performance", e.Sections[2].Name)
+}
+
+func TestWithRepo(t *testing.T) {
+	mockGitClient := &mocks.GitClient{}
+
+	mockGitHubClient := setupMockGitHubClient()
+	mockGitHubClient.On("GetFirstCommit").Return("e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5", nil).Once()
+
+	opts := &builder.BuilderOptions{
+		Repo:         "repo-owner/repo-name",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	}
+
+	builder := setupBuilder(opts)
+	changelog, err := builder.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "v1.0.0", entries[0].PrevTag)
+	assert.Equal(t, "e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5", entries[1].PrevTag)
+
+	// The local repository is never read.
+	mockGitClient.AssertNotCalled(t, "GetTags")
+	mockGitHubClient.AssertExpectations(t)
+}
+
+func TestWithRepoAndTheGitSource(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	_, err := builder.NewBuilder(builder.BuilderOptions{
+		Repo:      "repo-owner/repo-name",
+		Source:    builder.SourceGit,
+		GitClient: &mocks.GitClient{},
+	})
+
+	assert.EqualError(t, err, "the 'git' source reads the local repository and can not be used with a repository")
+}
+
+func TestWithPackage(t *testing.T) {
+	_ = configuration.InitConfig()
+	configuration.Config.Packages = map[string]configuration.Package{
+		"api": {TagPrefix: "api/", Paths: []string{"services/api/**"}, FileName: "services/api/CHANGELOG.md"},
+	}
+	defer func() { configuration.Config.Packages = map[string]configuration.Package{} }()
+
+	first := time.Date(2022, 4, 17, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 4, 18, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{
+		{Name: "api/v1.1.0", Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: second},
+		{Name: "web/v3.0.0", Sha: "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", Date: second},
+		{Name: "api/v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitClient.On("GetChangedFiles", "f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2").Return([]string{"services/web/main.go"}, nil)
+	mockGitClient.On("GetChangedFiles", "e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5").Return([]string{"services/api/go.mod"}, nil)
+	mockGitClient.On("GetChangedFiles", "d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0").Return([]string{"README.md", "services/api/main.go"}, nil)
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+	mockGitHubClient.On("GetPullRequestsBetweenDates", second, builder.Now()).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, second).Return([]githubclient.PullRequest{
+		{Number: 4, Title: "change the web app", User: "test-user", MergeCommitSha: "f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2"},
+		{Number: 3, Title: "change the api module", User: "test-user", MergeCommitSha: "e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5"},
+		{Number: 2, Title: "change the api", User: "test-user", MergeCommitSha: "d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, first).Return([]githubclient.PullRequest{}, nil)
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Package:      "api",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "api/v1.1.0", entries[0].Tag)
+	assert.Equal(t, "api/v1.0.0", entries[0].PrevTag)
+	assert.Equal(t, "api/v1.0.0", entries[1].Tag)
+
+	items := entries[0].GetSection("other")
+	assert.Len(t, items, 2)
+	assert.Equal(t, 3, items[0].Number)
+	assert.Equal(t, 2, items[1].Number)
+
+	b, err = builder.NewBuilder(builder.BuilderOptions{
+		Package:      "api",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	nextVersion, err := b.NextVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "api/v1.1.1", nextVersion)
+}
+
+func TestWithPackageAndRepo(t *testing.T) {
+	_ = configuration.InitConfig()
+	configuration.Config.Packages = map[string]configuration.Package{
+		"api": {TagPrefix: "api/", Paths: []string{"services/api/**"}, FileName: "services/api/CHANGELOG.md"},
+	}
+	defer func() { configuration.Config.Packages = map[string]configuration.Package{} }()
+
+	first := time.Date(2022, 4, 17, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{Name: "api/v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitHubClient.On("GetFirstCommit").Return("e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5", nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, builder.Now()).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, first).Return([]githubclient.PullRequest{
+		{Number: 2, Title: "change the web app", User: "test-user", MergeCommitSha: "f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2"},
+		{Number: 1, Title: "change the api", User: "test-user", MergeCommitSha: "d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0"},
+	}, nil)
+	mockGitHubClient.On("GetChangedFiles", 2).Return([]string{"services/web/main.go"}, nil)
+	mockGitHubClient.On("GetChangedFiles", 1).Return([]string{"README.md", "services/api/main.go"}, nil)
+
+	mockGitClient := &mocks.GitClient{}
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Repo:         "repo-owner/repo-name",
+		Package:      "api",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	// The changed files are read from GitHub because there is no local clone.
+	assert.Equal(t, []int{1}, getItemNumbers(changelog.GetEntries()[0].GetSection("other")))
+	mockGitClient.AssertNotCalled(t, "GetChangedFiles", mock.Anything)
+	mockGitHubClient.AssertExpectations(t)
+}
+
+func TestWithPackageAndNextVersion(t *testing.T) {
+	_ = configuration.InitConfig()
+	configuration.Config.Packages = map[string]configuration.Package{
+		"api": {TagPrefix: "api/", FileName: "services/api/CHANGELOG.md"},
+	}
+	defer func() { configuration.Config.Packages = map[string]configuration.Package{} }()
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{
+		{Name: "api/v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: safeParseTime()},
+	}, nil)
+	mockGitClient.On("GetLastCommit").Return("0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", nil)
+
+	mockGitHubClient := setupMockGitHubClient()
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Package:      "api",
+		NextVersion:  "v1.1.0",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+	assert.Equal(t, "api/v1.1.0", changelog.GetEntries()[0].Tag)
+	assert.Equal(t, "api/v1.0.0", changelog.GetEntries()[0].PrevTag)
+}
+
+func TestWithAnUnknownPackage(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	_, err := builder.NewBuilder(builder.BuilderOptions{
+		Package:      "api",
+		GitClient:    &mocks.GitClient{},
+		GitHubClient: &mocks.GitHubClient{},
+	})
+
+	assert.EqualError(t, err, "the package 'api' is not configured")
+}
+
+func setupMockGitHubClientWithTags(names ...string) *mocks.GitHubClient {
+	var tags []githubclient.Tag
+	for _, name := range names {
+		tags = append(tags, githubclient.Tag{
+			Name: name,
+			Sha:  "42d4c93b23eaf307c5f9712f4c62014fe38332bd",
+			Date: safeParseTime(),
+		})
+	}
+
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return(tags, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", safeParseTime(), safeParseTime()).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	return mockGitHubClient
+}
+
+func getTagNames(entries []*entry.Entry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Tag)
+	}
+
+	return names
+}
+
+func TestWithTagPatterns(t *testing.T) {
+	mockGitHubClient := setupMockGitHubClientWithTags("nightly", "v2.0.0", "helm-chart-1.0", "v1.1.0-rc.1", "v1.0.0")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Tags.Include = []string{"v*"}
+	configuration.Config.Tags.Exclude = []string{"*-rc.*"}
+	defer func() {
+		configuration.Config.Tags.Include = []string{}
+		configuration.Config.Tags.Exclude = []string{}
+	}()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2.0.0", "v1.0.0"}, getTagNames(changelog.GetEntries()))
+}
+
+func TestWithSemverOnlyTags(t *testing.T) {
+	mockGitHubClient := setupMockGitHubClientWithTags("nightly", "v2.0.0", "docs-2023", "helm-chart-1.0", "v1.0.0")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Tags.SemverOnly = true
+	defer func() { configuration.Config.Tags.SemverOnly = false }()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2.0.0", "v1.0.0"}, getTagNames(changelog.GetEntries()))
+}
+
+func TestWithSemverTagSort(t *testing.T) {
+	// v1.0.1 is a backport that was tagged after v2.0.0.
+	mockGitHubClient := setupMockGitHubClientWithTags("v1.0.1", "nightly", "v2.0.0", "v1.0.0")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Tags.Sort = builder.TagSortSemver
+	defer func() { configuration.Config.Tags.Sort = builder.TagSortDate }()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2.0.0", "v1.0.1", "v1.0.0", "nightly"}, getTagNames(changelog.GetEntries()))
+}
+
+func TestWithAnInvalidTagSort(t *testing.T) {
+	mockGitHubClient := setupMockGitHubClientWithTags("v1.0.0")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Tags.Sort = "name"
+	defer func() { configuration.Config.Tags.Sort = "date" }()
+
+	_, err := b.BuildChangelog()
+	assert.EqualError(t, err, "'name' is not a valid tag sort. Valid values are 'date' and 'semver'")
+}
+
+func setupMockGitHubClientWithPrereleases() *mocks.GitHubClient {
+	first := time.Date(2022, 4, 17, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 4, 18, 0, 0, 0, 0, time.UTC)
+	third := time.Date(2022, 4, 19, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{Name: "v2.0.0", Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: third},
+		{Name: "v2.0.0-rc.1", Sha: "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", Date: second},
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", third, builder.Now()).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", second, third).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "a fix for the release candidate", User: "test-user"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, second).Return([]githubclient.PullRequest{
+		{Number: 2, Title: "a new feature", User: "test-user"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, third).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "a fix for the release candidate", User: "test-user"},
+		{Number: 2, Title: "a new feature", User: "test-user"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, first).Return([]githubclient.PullRequest{
+		{Number: 1, Title: "the first release", User: "test-user"},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	return mockGitHubClient
+}
+
+func TestWithoutFoldedPrereleases(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		GitHubClient: setupMockGitHubClientWithPrereleases(),
+	}
+
+	b := setupBuilder(opts)
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []string{"v2.0.0", "v2.0.0-rc.1", "v1.0.0"}, getTagNames(entries))
+	assert.Equal(t, "v2.0.0-rc.1", entries[0].PrevTag)
+	assert.Len(t, entries[0].GetSection("other"), 1)
+}
+
+func TestWithFoldedPrereleases(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		GitHubClient: setupMockGitHubClientWithPrereleases(),
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Prereleases.Fold = true
+	defer func() { configuration.Config.Prereleases.Fold = false }()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []string{"v2.0.0", "v1.0.0"}, getTagNames(entries))
+	assert.Equal(t, "v1.0.0", entries[0].PrevTag)
+
+	items := entries[0].GetSection("other")
+	assert.Len(t, items, 2)
+	assert.Equal(t, 3, items[0].Number)
+	assert.Equal(t, 2, items[1].Number)
+}
+
+func TestWithFoldedPrereleasesAndKeptEntries(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		GitHubClient: setupMockGitHubClientWithPrereleases(),
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Prereleases.Fold = true
+	configuration.Config.Prereleases.KeepEntries = true
+	defer func() {
+		configuration.Config.Prereleases.Fold = false
+		configuration.Config.Prereleases.KeepEntries = false
+	}()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []string{"v2.0.0", "v2.0.0-rc.1", "v1.0.0"}, getTagNames(entries))
+	assert.Equal(t, "v1.0.0", entries[0].PrevTag)
+	assert.Len(t, entries[0].GetSection("other"), 2)
+	assert.Equal(t, "v1.0.0", entries[1].PrevTag)
+	assert.Len(t, entries[1].GetSection("other"), 1)
+}
+
+func TestWithFoldedPrereleasesAndNoStableVersion(t *testing.T) {
+	mockGitHubClient := setupMockGitHubClientWithTags("v2.0.0-rc.2", "v2.0.0-rc.1", "v1.0.0")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Prereleases.Fold = true
+	defer func() { configuration.Config.Prereleases.Fold = false }()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2.0.0-rc.2", "v2.0.0-rc.1", "v1.0.0"}, getTagNames(changelog.GetEntries()))
+}
+
+// setupMockClientsWithReleaseLines returns clients for a repository where
+// v1.4.3 was tagged on release/1.4 after v2.0.0 was tagged on main. The tags
+// that are reachable from HEAD show which branch is checked out.
+func setupMockClientsWithReleaseLines(head string, reachableFromHead ...string) (*mocks.GitClient, *mocks.GitHubClient) {
+	first := time.Date(2022, 4, 17, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 4, 18, 0, 0, 0, 0, time.UTC)
+	third := time.Date(2022, 4, 19, 0, 0, 0, 0, time.UTC)
+	fourth := time.Date(2022, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	mockGitClient := &mocks.GitClient{}
+	mockGitClient.On("GetTags").Return([]gitclient.Tag{
+		{Name: "v1.4.3", Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: fourth},
+		{Name: "v2.0.0", Sha: "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", Date: third},
+		{Name: "v1.4.2", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitClient.On("GetTagsReachableFrom", "release/1.4").Return([]string{"v1.4.2", "v1.4.3"}, nil)
+	mockGitClient.On("GetTagsReachableFrom", "HEAD").Return(reachableFromHead, nil)
+	mockGitClient.On("GetLastCommit").Return("0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", nil)
+	mockGitClient.On("GetCommitsBetween", "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", head).Return([]gitclient.Commit{
+		{Sha: "e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5", Date: fourth},
+	}, nil)
+	mockGitClient.On("GetCommitsBetween", "42d4c93b23eaf307c5f9712f4c62014fe38332bd", "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1").Return([]gitclient.Commit{
+		{Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: second},
+	}, nil)
+	mockGitClient.On("GetCommitsBetween", "", "42d4c93b23eaf307c5f9712f4c62014fe38332bd").Return([]gitclient.Commit{
+		{Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+	mockGitHubClient.On("GetPullRequestsBetweenDates", fourth, fourth).Return([]githubclient.PullRequest{
+		{Number: 4, Title: "a backported breaking change", User: "test-user", MergeCommitSha: "e2b6b7e9c2b7c1a8f1c4a5b3d2e1f0a9b8c7d6e5", Labels: []githubclient.PullRequestLabel{{Name: "backwards-incompatible"}}},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", second, second).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "merged on main", User: "test-user", MergeCommitSha: "ffffffffffffffffffffffffffffffffffffffff"},
+		{Number: 2, Title: "a backported fix", User: "test-user", MergeCommitSha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, first).Return([]githubclient.PullRequest{
+		{Number: 1, Title: "the first release", User: "test-user", MergeCommitSha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd"},
+	}, nil)
+
+	return mockGitClient, mockGitHubClient
+}
+
+func TestWithBranch(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	mockGitClient, mockGitHubClient := setupMockClientsWithReleaseLines("release/1.4")
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Branch:       "release/1.4",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []string{"v1.4.3", "v1.4.2"}, getTagNames(entries))
+	assert.Equal(t, "v1.4.2", entries[0].PrevTag)
+
+	items := entries[0].GetSection("other")
+	assert.Len(t, items, 1)
+	assert.Equal(t, 2, items[0].Number)
+
+	assert.Len(t, changelog.GetUnreleased(), 1)
+	assert.Equal(t, 4, changelog.GetUnreleased()[0].Number)
+}
+
+func TestWithLine(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	mockGitClient, mockGitHubClient := setupMockClientsWithReleaseLines("HEAD", "v1.4.2", "v1.4.3")
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Line:         "1.x",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []string{"v1.4.3", "v1.4.2"}, getTagNames(entries))
+	assert.Equal(t, "v1.4.2", entries[0].PrevTag)
+	assert.Len(t, entries[0].GetSection("other"), 1)
+	mockGitClient.AssertNotCalled(t, "GetTagsReachableFrom", "release/1.4")
+}
+
+func TestWithLineAndAutoNextVersion(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{line: "1.x", expected: "v1.5.0"},
+		{line: "1.4.x", expected: "v1.4.4"},
+	}
+
+	for _, tt := range tests {
+		mockGitClient, mockGitHubClient := setupMockClientsWithReleaseLines("HEAD", "v1.4.2", "v1.4.3")
+
+		b, err := builder.NewBuilder(builder.BuilderOptions{
+			Line:         tt.line,
+			GitClient:    mockGitClient,
+			GitHubClient: mockGitHubClient,
+		})
+		assert.NoError(t, err)
+
+		nextVersion, err := b.NextVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, nextVersion)
+	}
+}
+
+func TestWithLineAndANextVersionOutsideOfIt(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	mockGitClient, mockGitHubClient := setupMockClientsWithReleaseLines("HEAD", "v1.4.2", "v1.4.3")
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Line:         "1.x",
+		NextVersion:  "v2.1.0",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	_, err = b.BuildChangelog()
+	assert.EqualError(t, err, "'v2.1.0' is not in the 1.x version line")
+}
+
+func TestWithLineWhenHEADIsNotOnTheLine(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	// main is checked out, so the changes after v1.4.3 are not on the line.
+	mockGitClient, mockGitHubClient := setupMockClientsWithReleaseLines("HEAD", "v1.4.2", "v2.0.0")
+
+	b, err := builder.NewBuilder(builder.BuilderOptions{
+		Line:         "1.x",
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	})
+	assert.NoError(t, err)
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.4.3", "v1.4.2"}, getTagNames(changelog.GetEntries()))
+	assert.Empty(t, changelog.GetUnreleased())
+	mockGitClient.AssertNotCalled(t, "GetCommitsBetween", "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", "HEAD")
+
+	for _, nextVersion := range []string{builder.NextVersionAuto, "v1.5.0"} {
+		b, err := builder.NewBuilder(builder.BuilderOptions{
+			Line:         "1.x",
+			NextVersion:  nextVersion,
+			GitClient:    mockGitClient,
+			GitHubClient: mockGitHubClient,
+		})
+		assert.NoError(t, err)
+
+		_, err = b.BuildChangelog()
+		assert.EqualError(t, err, "HEAD is not on the 1.x version line. Use --branch to choose the branch of the line")
+	}
+}
+
+func TestWithAnInvalidLine(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	for _, line := range []string{"x", "1.4.2.x", "one.x"} {
+		_, err := builder.NewBuilder(builder.BuilderOptions{
+			Line:         line,
+			GitClient:    &mocks.GitClient{},
+			GitHubClient: setupMockGitHubClient(),
+		})
+
+		assert.EqualError(t, err, fmt.Sprintf("'%s' is not a valid version line. Use a major or minor version such as 1.x or 1.4.x", line))
+	}
+}
+
+func TestWithLineAndRepo(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	_, err := builder.NewBuilder(builder.BuilderOptions{
+		Repo:      "repo-owner/repo-name",
+		Line:      "1.4.x",
+		GitClient: &mocks.GitClient{},
+	})
+
+	assert.EqualError(t, err, "a version line uses the commit graph of the local repository and can not be used with a repository")
+}
+
+func TestWithBranchAndRepo(t *testing.T) {
+	_ = configuration.InitConfig()
+
+	_, err := builder.NewBuilder(builder.BuilderOptions{
+		Repo:      "repo-owner/repo-name",
+		Branch:    "release/1.4",
+		GitClient: &mocks.GitClient{},
+	})
+
+	assert.EqualError(t, err, "a branch is read from the local repository and can not be used with a repository")
+}
+
+func TestWithContributors(t *testing.T) {
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	labels := []githubclient.PullRequestLabel{{Name: "enhancement"}}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{Name: "v2.0.0", Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: second},
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", second, builder.Now()).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, second).Return([]githubclient.PullRequest{
+		{Number: 5, Title: "pr 5", User: "bob", Labels: labels},
+		{Number: 4, Title: "pr 4", User: "Alice", Labels: labels},
+		{Number: 3, Title: "pr 3", User: "dependabot", IsBot: true, Labels: labels},
+		{Number: 2, Title: "pr 2", User: "release-user", Labels: labels},
+		{Number: 6, Title: "pr 6", User: "carol", Labels: []githubclient.PullRequestLabel{{Name: "maintenance"}}},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, first).Return([]githubclient.PullRequest{
+		{Number: 1, Title: "pr 1", User: "alice", Labels: labels},
+	}, nil)
+	mockGitHubClient.On("GetFirstMergeDate", "bob").Return(first, nil).Once()
+	mockGitHubClient.On("GetFirstMergeDate", "Alice").Return(first.Add(-time.Hour), nil).Once()
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Contributors.Enabled = true
+	configuration.Config.Contributors.Exclude = []string{"release-user"}
+	defer func() {
+		configuration.Config.Contributors.Enabled = false
+		configuration.Config.Contributors.Exclude = []string{}
+	}()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []entry.Contributor{
+		{Login: "Alice", FirstTime: false},
+		{Login: "bob", FirstTime: true},
+	}, entries[0].Contributors)
+
+	assert.Equal(t, []entry.Contributor{
+		{Login: "alice", FirstTime: true},
+	}, entries[1].Contributors)
+
+	mockGitHubClient.AssertExpectations(t)
+}
+
+func TestWithContributorsLooksUpEachAuthorOnce(t *testing.T) {
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	third := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{Name: "v3.0.0", Sha: "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", Date: third},
+		{Name: "v2.0.0", Sha: "0d724ba5b4235aa88d45a20f4ecd8db4b4695cf1", Date: second},
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", third, builder.Now()).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", second, third).Return([]githubclient.PullRequest{
+		{Number: 3, Title: "pr 3", User: "Bob"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, second).Return([]githubclient.PullRequest{
+		{Number: 2, Title: "pr 2", User: "bob"},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, first).Return([]githubclient.PullRequest{}, nil)
+	mockGitHubClient.On("GetFirstMergeDate", "Bob").Return(first.Add(24*time.Hour), nil).Once()
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	opts := &builder.BuilderOptions{
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.Contributors.Enabled = true
+	defer func() { configuration.Config.Contributors.Enabled = false }()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	entries := changelog.GetEntries()
+	assert.Equal(t, []entry.Contributor{{Login: "Bob", FirstTime: false}}, entries[0].Contributors)
+	assert.Equal(t, []entry.Contributor{{Login: "bob", FirstTime: true}}, entries[1].Contributors)
+
+	mockGitHubClient.AssertNumberOfCalls(t, "GetFirstMergeDate", 1)
+}
+
+func setupMockGitHubClientWithAuthors() *mocks.GitHubClient {
+	builder.Now = func() time.Time {
+		return safeParseTime()
+	}
+
+	labels := []githubclient.PullRequestLabel{{Name: "enhancement"}}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: safeParseTime()},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", safeParseTime(), safeParseTime()).Return([]githubclient.PullRequest{
+		{Number: 5, Title: "Add a feature", User: "octocat", Labels: labels},
+		{Number: 4, Title: "Bump golang.org/x/text from 0.13.0 to 0.14.0", User: "dependabot", IsBot: true},
+		{Number: 3, Title: "Update module github.com/spf13/cobra to v1.8.0", User: "renovate[bot]"},
+		{Number: 2, Title: "chore(deps): update actions/checkout", User: "hubot"},
+		{Number: 1, Title: "Fix a bug", User: "Hubot", Labels: labels},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	return mockGitHubClient
+}
+
+func getItemNumbers(items []entry.Item) []int {
+	var numbers []int
+	for _, item := range items {
+		numbers = append(numbers, item.Number)
+	}
+
+	return numbers
+}
+
+func TestWithExcludedAuthorsAndTitles(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		GitHubClient: setupMockGitHubClientWithAuthors(),
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.ExcludedAuthors = []string{"*[bot]"}
+	configuration.Config.ExcludedTitlePatterns = []string{`^chore\(deps\)`}
+	defer func() {
+		configuration.Config.ExcludedAuthors = []string{}
+		configuration.Config.ExcludedTitlePatterns = []string{}
+	}()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{5, 1}, getItemNumbers(changelog.GetUnreleased()))
+
+	e := changelog.GetEntries()[0]
+	assert.Equal(t, []int{5, 1}, getItemNumbers(e.GetSection("added")))
+	assert.Nil(t, e.GetSection("other"))
+}
+
+func TestWithIncludedAuthors(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		GitHubClient: setupMockGitHubClientWithAuthors(),
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.IncludedAuthors = []string{"hubot"}
+	defer func() { configuration.Config.IncludedAuthors = []string{} }()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{2, 1}, getItemNumbers(changelog.GetUnreleased()))
+
+	e := changelog.GetEntries()[0]
+	assert.Equal(t, []int{1}, getItemNumbers(e.GetSection("added")))
+	assert.Equal(t, []int{2}, getItemNumbers(e.GetSection("other")))
+}
+
+func TestWithAnInvalidTitlePattern(t *testing.T) {
+	opts := &builder.BuilderOptions{
+		GitHubClient: setupMockGitHubClientWithAuthors(),
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.ExcludedTitlePatterns = []string{`chore(deps`}
+	defer func() { configuration.Config.ExcludedTitlePatterns = []string{} }()
+
+	_, err := b.BuildChangelog()
+	assert.ErrorContains(t, err, "'chore(deps' is not a valid title pattern")
+}
+
+func TestWithDirectCommits(t *testing.T) {
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	builder.Now = func() time.Time {
+		return time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	mockGitHubClient := &mocks.GitHubClient{}
+	mockGitHubClient.On("GetTags").Return([]githubclient.Tag{
+		{Name: "v1.0.0", Sha: "42d4c93b23eaf307c5f9712f4c62014fe38332bd", Date: first},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", first, builder.Now()).Return([]githubclient.PullRequest{
+		// Merged with a rebase, so both of its commits are on the branch.
+		{Number: 3, Title: "Fix a bug", User: "test-user", MergeCommitSha: "d4a1c2b", Labels: []githubclient.PullRequestLabel{{Name: "bug"}}, CommitSubjects: []string{"Add a test for the bug", "Fix a bug"}},
+	}, nil)
+	mockGitHubClient.On("GetPullRequestsBetweenDates", time.Time{}, first).Return([]githubclient.PullRequest{
+		{Number: 1, Title: "Add a feature", User: "test-user", MergeCommitSha: "a1b2c3d", Labels: []githubclient.PullRequestLabel{{Name: "enhancement"}}},
+		{Number: 2, Title: "Update the build", User: "test-user", MergeCommitSha: "b2c3d4e", Labels: []githubclient.PullRequestLabel{{Name: "maintenance"}}},
+	}, nil)
+	mockGitHubClient.On("GetRepoName").Return(repoName)
+	mockGitHubClient.On("GetRepoOwner").Return(repoOwner)
+	mockGitHubClient.On("GetRepoHost").Return("github.com")
+
+	mockGitClient := setupMockGitClient()
+	mockGitClient.On("GetFirstParentCommitsBetween", "", "42d4c93b23eaf307c5f9712f4c62014fe38332bd").Return([]gitclient.Commit{
+		{Sha: "c3d4e5f", Subject: "Fix a typo in the README", Date: first},
+		{Sha: "b2c3d4e", Subject: "Update the build"},
+		{Sha: "a1b2c3d", Subject: "Add a feature"},
+		{Sha: "9f8e7d6", Subject: "Initial commit"},
+	}, nil)
+	mockGitClient.On("GetFirstParentCommitsBetween", "42d4c93b23eaf307c5f9712f4c62014fe38332bd", "HEAD").Return([]gitclient.Commit{
+		{Sha: "f6e5d4c", Subject: "Bump version to 1.0.1"},
+		{Sha: "e5f6a7b", Subject: "Fix the release workflow"},
+		{Sha: "d4a1c2b", Subject: "Fix a bug"},
+		{Sha: "d3c2b1a", Subject: "Add a test for the bug"},
+		{Sha: "c2b1a0f", Subject: "Fix a bug in the parser (#4)"},
+	}, nil)
+
+	opts := &builder.BuilderOptions{
+		GitClient:    mockGitClient,
+		GitHubClient: mockGitHubClient,
+	}
+
+	b := setupBuilder(opts)
+	configuration.Config.DirectCommits.Enabled = true
+	configuration.Config.ExcludedTitlePatterns = []string{`^Bump version`}
+	defer func() {
+		configuration.Config.DirectCommits.Enabled = false
+		configuration.Config.ExcludedTitlePatterns = []string{}
+	}()
+
+	changelog, err := b.BuildChangelog()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []entry.Item{
+		{Title: "Fix a bug", Number: 3, Author: "test-user", Labels: []string{"bug"}, MergeSha: "d4a1c2b"},
+		{Title: "Fix the release workflow", MergeSha: "e5f6a7b"},
+	}, changelog.GetUnreleased())
+
+	e := changelog.GetEntries()[0]
+	assert.Equal(t, []int{1}, getItemNumbers(e.GetSection("added")))
+	assert.Equal(t, []entry.Item{
+		{Title: "Fix a typo in the README", MergeSha: "c3d4e5f", MergedAt: first},
+		{Title: "Initial commit", MergeSha: "9f8e7d6"},
+	}, e.GetSection("other"))
+}
+
+func TestWithDirectCommitsAndRepo(t *testing.T) {
+	_ = configuration.InitConfig()
+	configuration.Config.DirectCommits.Enabled = true
+	defer func() { configuration.Config.DirectCommits.Enabled = false }()
+
+	_, err := builder.NewBuilder(builder.BuilderOptions{
+		Repo:      "repo-owner/repo-name",
+		GitClient: &mocks.GitClient{},
+	})
+
+	assert.EqualError(t, err, "direct_commits reads the local repository and can not be used with a repository")
 }
@@ -6,12 +6,21 @@ import (
 	"github.com/chelnak/gh-changelog/pkg/entry"
 )
 
+// DefaultHost is the host that is used when a changelog does not have one.
+const DefaultHost = "github.com"
+
 // Changelog is an interface for a changelog datastructure.
 type Changelog interface {
 	GetRepoName() string
 	GetRepoOwner() string
-	GetUnreleased() []string
-	AddUnreleased([]string)
+	GetRepoHost() string
+	SetRepoHost(string)
+	GetPreamble() string
+	SetPreamble(string)
+	GetUnreleased() []entry.Item
+	AddUnreleased([]entry.Item)
+	GetUnreleasedDescription() string
+	SetUnreleasedDescription(string)
 	Insert(entry.Entry)
 	GetEntries() []*entry.Entry
 	Head() *entry.Entry
@@ -25,7 +34,11 @@ type changelog struct {
 
 	repoName   string
 	repoOwner  string
-	unreleased []string
+	repoHost   string
+	preamble   string
+	unreleased []entry.Item
+
+	unreleasedDescription string
 }
 
 // GetRepoName returns the name of the repository.
@@ -38,15 +51,55 @@ func (c *changelog) GetRepoOwner() string {
 	return c.repoOwner
 }
 
+// GetRepoHost returns the host of the repository. It defaults to github.com
+// when no host has been set.
+func (c *changelog) GetRepoHost() string {
+	if c.repoHost == "" {
+		return DefaultHost
+	}
+
+	return c.repoHost
+}
+
+// SetRepoHost sets the host of the repository, for example the hostname of a
+// GitHub Enterprise Server instance.
+func (c *changelog) SetRepoHost(host string) {
+	c.repoHost = host
+}
+
+// GetPreamble returns the text that appears before the first entry in the
+// changelog. It is empty unless the changelog was read from a file.
+func (c *changelog) GetPreamble() string {
+	return c.preamble
+}
+
+// SetPreamble sets the text that appears before the first entry in the
+// changelog.
+func (c *changelog) SetPreamble(preamble string) {
+	c.preamble = preamble
+}
+
 // GetUnreleased returns the unreleased changes if any exist.
-func (c *changelog) GetUnreleased() []string {
+func (c *changelog) GetUnreleased() []entry.Item {
 	return c.unreleased
 }
 
 // AddUnreleased adds a list of unreleased changes to the changelog.
-// This only needs to be a slice of strings.
-func (c *changelog) AddUnreleased(entry []string) {
-	c.unreleased = append(c.unreleased, entry...)
+func (c *changelog) AddUnreleased(items []entry.Item) {
+	c.unreleased = append(c.unreleased, items...)
+}
+
+// GetUnreleasedDescription returns the text that appears before the items of
+// the Unreleased section. It is empty unless the changelog was read from a
+// file.
+func (c *changelog) GetUnreleasedDescription() string {
+	return c.unreleasedDescription
+}
+
+// SetUnreleasedDescription sets the text that appears before the items of the
+// Unreleased section.
+func (c *changelog) SetUnreleasedDescription(description string) {
+	c.unreleasedDescription = description
 }
 
 // Insert inserts a new entry into the changelog.
@@ -93,6 +146,6 @@ func NewChangelog(repoOwner string, repoName string) Changelog {
 	return &changelog{
 		repoName:   repoName,
 		repoOwner:  repoOwner,
-		unreleased: []string{},
+		unreleased: []entry.Item{},
 	}
 }
@@ -33,10 +33,18 @@ func TetstNewChangelog(t *testing.T) {
 	assert.Equal(t, 0, len(testChangelog.GetUnreleased()))
 }
 
+func TestRepoHost(t *testing.T) {
+	var testChangelog = changelog.NewChangelog(repoOwner, repoName)
+	assert.Equal(t, "github.com", testChangelog.GetRepoHost())
+
+	testChangelog.SetRepoHost("ghes.example.com")
+	assert.Equal(t, "ghes.example.com", testChangelog.GetRepoHost())
+}
+
 func TestInsert(t *testing.T) {
 	var testChangelog = changelog.NewChangelog(repoOwner, repoName)
 	for _, e := range entries {
-		err := e.Append("added", "test")
+		err := e.Append("added", entry.Item{Text: "test"})
 		assert.Nil(t, err)
 
 		testChangelog.Insert(e)
@@ -44,15 +52,15 @@ func TestInsert(t *testing.T) {
 
 	entries := testChangelog.GetEntries()
 	assert.Equal(t, 2, len(entries))
-	assert.Equal(t, 1, len(entries[0].Added))
-	assert.Equal(t, "test", entries[0].Added[0])
+	assert.Equal(t, 1, len(entries[0].GetSection("added")))
+	assert.Equal(t, "test", entries[0].GetSection("added")[0].Text)
 }
 
 func TestTail(t *testing.T) {
 	var testChangelog = changelog.NewChangelog(repoOwner, repoName)
 
 	for _, e := range entries {
-		err := e.Append("added", "test")
+		err := e.Append("added", entry.Item{Text: "test"})
 		assert.Nil(t, err)
 
 		testChangelog.Insert(e)
@@ -76,7 +84,7 @@ func TestHead(t *testing.T) {
 	}
 
 	for _, e := range entries {
-		err := e.Append("added", "test")
+		err := e.Append("added", entry.Item{Text: "test"})
 		assert.Nil(t, err)
 
 		testChangelog.Insert(e)
@@ -88,18 +96,18 @@ func TestHead(t *testing.T) {
 
 func TestAddUnreleased(t *testing.T) {
 	var testChangelog = changelog.NewChangelog(repoOwner, repoName)
-	testChangelog.AddUnreleased([]string{"test"})
+	testChangelog.AddUnreleased([]entry.Item{{Text: "test"}})
 
 	unreleased := testChangelog.GetUnreleased()
 
 	assert.Equal(t, 1, len(unreleased))
-	assert.Equal(t, "test", unreleased[0])
+	assert.Equal(t, "test", unreleased[0].Text)
 }
 
 func TestGetEntries(t *testing.T) {
 	var testChangelog = changelog.NewChangelog(repoOwner, repoName)
 	for _, e := range entries {
-		err := e.Append("added", "test")
+		err := e.Append("added", entry.Item{Text: "test"})
 		assert.Nil(t, err)
 
 		testChangelog.Insert(e)
@@ -2,67 +2,120 @@
 package entry
 
 import (
-	"fmt"
-	"reflect"
+	"errors"
+	"sort"
 	"strings"
 	"time"
-
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
+// Item represents a single change in the changelog. Items that are built
+// from a pull request carry its details so that rendering can be left to the
+// writer. Items for commits that were pushed without a pull request only have
+// a Title, MergeSha and MergedAt. Items that are read from an existing
+// changelog keep the original text in Text, including any nested lists or
+// paragraphs that follow it.
+type Item struct {
+	Title    string
+	Number   int
+	Author   string
+	URL      string
+	Labels   []string
+	MergeSha string
+	MergedAt time.Time
+	Text     string
+}
+
+// Section represents a named group of changes in an entry, for example
+// Added or Fixed. Description holds any text that appears between the
+// heading of the section and its items.
+type Section struct {
+	Name        string
+	Description string
+	Items       []Item
+	Line        int // The line of the heading when the section was read from a file.
+}
+
+// Contributor is the author of at least one pull request in an entry.
+// FirstTime is true when none of their pull requests were merged before the
+// previous release.
+type Contributor struct {
+	Login     string
+	FirstTime bool
+}
+
 // Entry represents a single entry in the changelog
 type Entry struct {
 	Previous *Entry // Get or Set the previous entry in the changelog.
 	Next     *Entry // Get or Set the next entry in the changelog.
 
-	Tag        string
-	PrevTag    string
-	Date       time.Time
-	Added      []string
-	Changed    []string
-	Deprecated []string
-	Removed    []string
-	Fixed      []string
-	Security   []string
-	Other      []string
+	Tag          string
+	PrevTag      string
+	CompareURL   string // The URL of the Full Changelog link when the entry was read from a file.
+	Date         time.Time
+	Description  string // Any text that appears before the first section.
+	Sections     []Section
+	Contributors []Contributor
+	Line         int // The line of the heading when the entry was read from a file.
 }
 
-// Append updates the given section in the entry..
-func (e *Entry) Append(section string, entry string) error {
-	switch strings.ToLower(section) {
-	case "added":
-		e.Added = append(e.Added, entry)
-	case "changed":
-		e.Changed = append(e.Changed, entry)
-	case "deprecated":
-		e.Deprecated = append(e.Deprecated, entry)
-	case "removed":
-		e.Removed = append(e.Removed, entry)
-	case "fixed":
-		e.Fixed = append(e.Fixed, entry)
-	case "security":
-		e.Security = append(e.Security, entry)
-	case "other":
-		e.Other = append(e.Other, entry)
-	default:
-		return fmt.Errorf("unknown entry type '%s'", section)
+// Append updates the given section in the entry. Section names are matched
+// case-insensitively. If the section does not exist, it is created.
+func (e *Entry) Append(section string, item Item) error {
+	if strings.TrimSpace(section) == "" {
+		return errors.New("section name cannot be empty")
+	}
+
+	for i := range e.Sections {
+		if strings.EqualFold(e.Sections[i].Name, section) {
+			e.Sections[i].Items = append(e.Sections[i].Items, item)
+			return nil
+		}
 	}
 
+	e.Sections = append(e.Sections, Section{
+		Name:  section,
+		Items: []Item{item},
+	})
+
 	return nil
 }
 
-// GetSection uses reflection to return a given section in the entry.
+// GetSection returns the items in a given section of the entry.
 // If the section does not exist, an empty slice is returned.
-func (e *Entry) GetSection(section string) []string {
-	title := cases.Title(language.English)
-	ref := reflect.ValueOf(e).Elem().FieldByName(title.String(section))
-	if ref.IsValid() {
-		return ref.Interface().([]string)
+func (e *Entry) GetSection(section string) []Item {
+	for _, s := range e.Sections {
+		if strings.EqualFold(s.Name, section) {
+			return s.Items
+		}
 	}
 	return nil
 }
 
+// SortSections orders the sections in the entry by the given list of section
+// names. Sections that are not in the list are moved to the end and keep
+// their current order.
+func (e *Entry) SortSections(order []string) {
+	rank := make(map[string]int)
+	for i, name := range order {
+		rank[normalizeSectionName(name)] = i
+	}
+
+	position := func(s Section) int {
+		if i, ok := rank[normalizeSectionName(s.Name)]; ok {
+			return i
+		}
+		return len(order)
+	}
+
+	sort.SliceStable(e.Sections, func(i, j int) bool {
+		return position(e.Sections[i]) < position(e.Sections[j])
+	})
+}
+
+func normalizeSectionName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", " "))
+}
+
 // NewEntry creates a new entry (node) that can be added to the changelog datastructure.
 func NewEntry(tag string, date time.Time) Entry {
 	return Entry{
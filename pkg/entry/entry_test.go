@@ -36,7 +36,7 @@ func TestPrevious(t *testing.T) {
 	}
 
 	for _, e := range entries {
-		err := e.Append("added", "test")
+		err := e.Append("added", entry.Item{Text: "test"})
 		assert.Nil(t, err)
 
 		testChangelog.Insert(e)
@@ -62,7 +62,7 @@ func TestNext(t *testing.T) {
 	}
 
 	for _, e := range entries {
-		err := e.Append("added", "test")
+		err := e.Append("added", entry.Item{Text: "test"})
 		assert.Nil(t, err)
 
 		testChangelog.Insert(e)
@@ -98,35 +98,67 @@ func TestAppend(t *testing.T) {
 		{
 			name: "other",
 		},
+		{
+			name: "performance",
+		},
+		{
+			name: "Breaking Changes",
+		},
 	}
 
 	e := entry.NewEntry("v2.0.0", time.Time{})
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("Appends a line to section: %s", test.name), func(t *testing.T) {
-			err := e.Append(test.name, fmt.Sprintf("test %s", test.name))
+			err := e.Append(test.name, entry.Item{Text: fmt.Sprintf("test %s", test.name)})
 			assert.Nil(t, err)
 
 			section := e.GetSection(test.name)
 			assert.Equal(t, 1, len(section))
-			assert.Regexp(t, fmt.Sprintf("test %s", test.name), section[0])
+			assert.Regexp(t, fmt.Sprintf("test %s", test.name), section[0].Text)
 		})
 	}
 }
 
-func TestReturnsAnErrorWhenAppendingToAnInvalidSection(t *testing.T) {
+func TestReturnsAnErrorWhenAppendingToAnEmptySection(t *testing.T) {
 	e := entry.NewEntry("v2.0.0", time.Time{})
-	err := e.Append("invalid", "test")
+	err := e.Append("", entry.Item{Text: "test"})
 	assert.NotNil(t, err)
 }
 
+func TestAppendMatchesSectionsCaseInsensitively(t *testing.T) {
+	e := entry.NewEntry("v2.0.0", time.Time{})
+	assert.Nil(t, e.Append("Added", entry.Item{Text: "test 1"}))
+	assert.Nil(t, e.Append("added", entry.Item{Text: "test 2"}))
+
+	assert.Len(t, e.Sections, 1)
+	assert.Equal(t, "Added", e.Sections[0].Name)
+	assert.Equal(t, []entry.Item{{Text: "test 1"}, {Text: "test 2"}}, e.GetSection("ADDED"))
+}
+
+func TestSortSections(t *testing.T) {
+	e := entry.NewEntry("v2.0.0", time.Time{})
+	for _, section := range []string{"Other", "Documentation", "Added", "Breaking Changes", "Fixed"} {
+		assert.Nil(t, e.Append(section, entry.Item{Text: "test"}))
+	}
+
+	e.SortSections([]string{"breaking_changes", "added", "fixed", "other"})
+
+	var names []string
+	for _, section := range e.Sections {
+		names = append(names, section.Name)
+	}
+
+	assert.Equal(t, []string{"Breaking Changes", "Added", "Fixed", "Other", "Documentation"}, names)
+}
+
 func TestGetSection(t *testing.T) {
 	e := entry.NewEntry("v2.0.0", time.Time{})
-	err := e.Append("added", "test")
+	err := e.Append("added", entry.Item{Text: "test"})
 	assert.Nil(t, err)
 
 	section := e.GetSection("added")
 	assert.Equal(t, 1, len(section))
-	assert.Equal(t, "test", section[0])
+	assert.Equal(t, "test", section[0].Text)
 
 	section = e.GetSection("invalid")
 	assert.Equal(t, 0, len(section))
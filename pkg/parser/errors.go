@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes a problem that was found while parsing a changelog.
+// Line and Column are 1-based and point to the offending text.
+type ParseError struct {
+	Path    string
+	Line    int
+	Column  int
+	Text    string
+	Message string
+	Hint    string
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Message)
+	if e.Text != "" {
+		msg = fmt.Sprintf("%s %q", msg, e.Text)
+	}
+
+	if e.Hint != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Hint)
+	}
+
+	return msg
+}
+
+// ParseErrors is a list of problems that were found while parsing a
+// changelog. It is returned by ParseAll.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap allows errors.As to be used to find a ParseError in the list.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
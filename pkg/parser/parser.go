@@ -5,14 +5,49 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/chelnak/gh-changelog/internal/utils"
+	"github.com/chelnak/gh-changelog/internal/version"
 	"github.com/chelnak/gh-changelog/pkg/changelog"
 	"github.com/chelnak/gh-changelog/pkg/entry"
-	"github.com/gomarkdown/markdown/ast"
-	mdparser "github.com/gomarkdown/markdown/parser"
+)
+
+var (
+	// Title [#123](https://github.com/owner/repo/pull/123) ([user](https://github.com/user))
+	itemRegex = regexp.MustCompile(`^(.*) \[#(\d+)\]\(([^)]+)\) \(\[([^\]]+)\]\([^)]+\)\)$`)
+
+	// ## [v1.0.0](https://github.com/owner/repo/tree/v1.0.0) - 2022-04-15
+	// ## v1.0.0 - 2022-04-15
+	entryHeadingRegex = regexp.MustCompile(`^## (?:\[([^\]]+)\]\([^)]*\)|(\S+))(?: - (.*))?$`)
+
+	// [Full Changelog](https://github.com/owner/repo/compare/v0.9.0...v1.0.0)
+	fullChangelogRegex = regexp.MustCompile(`^\[Full Changelog\]\(([^)]*/compare/(.+)\.\.\.([^)]+))\)$`)
+
+	// [@user](https://github.com/user) made their first contribution
+	// @user
+	contributorRegex = regexp.MustCompile(`^(?:\[@([^\]]+)\]\([^)]*\)|@(\S+))( made their first contribution)?$`)
+)
+
+const (
+	unreleasedHeading   = "Unreleased"
+	contributorsHeading = "Contributors"
+)
+
+// block is the part of the changelog that lines are currently being added to.
+type block int
+
+const (
+	blockPreamble block = iota
+	blockUnreleased
+	blockEntry
+	blockSection
+	blockItem
+	blockContributors
+	blockContributor
 )
 
 type parser struct {
@@ -24,6 +59,7 @@ type parser struct {
 // Parser is an interface for parsing markdown changelogs.
 type Parser interface {
 	Parse() (changelog.Changelog, error)
+	ParseAll() (changelog.Changelog, error)
 }
 
 // NewParser returns a new parser for the given changelog..
@@ -35,19 +71,63 @@ func NewParser(path, repoOwner, repoName string) Parser {
 	}
 }
 
+// state holds the position of the parser in the changelog.
+type state struct {
+	path       string
+	collectAll bool
+	errors     ParseErrors
+	line       int // the number of the line that is being parsed
+	blockLine  int // the number of the line that the current block started on
+	block      block
+	inList     bool // true while in the Unreleased section or a section of an entry
+	entry      *entry.Entry
+	section    string
+	lines      []string
+	preamble   []string
+	unreleased []entry.Item
+	entries    []*entry.Entry
+
+	unreleasedDescription string // the text before the items of the Unreleased section
+}
+
 // Parse parses the changelog and returns a Changelog struct.
+//
+// Content that is not understood, such as paragraphs, nested lists and code
+// blocks, is kept with the part of the changelog that it appears in so that
+// writing the changelog with the standard template reproduces the original
+// file.
+//
+// Parsing stops at the first problem, which is returned as a *ParseError.
 func (p *parser) Parse() (changelog.Changelog, error) {
-	repoContext, err := utils.GetRepoContext()
-	if err != nil {
-		return nil, err
-	}
+	return p.parse(false)
+}
 
-	if p.repoOwner == "" {
-		p.repoOwner = repoContext.Owner
-	}
+// ParseAll parses the changelog in the same way as Parse but does not stop
+// when a problem is found. All problems are returned as ParseErrors along
+// with the changelog that could be parsed.
+func (p *parser) ParseAll() (changelog.Changelog, error) {
+	return p.parse(true)
+}
 
-	if p.repoName == "" {
-		p.repoName = repoContext.Name
+func (p *parser) parse(collectAll bool) (changelog.Changelog, error) {
+	// The repository only needs to be resolved from the current directory
+	// when it was not given.
+	repoHost := utils.DefaultHost()
+	if p.repoOwner == "" || p.repoName == "" {
+		repoContext, err := utils.GetRepoContext()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.repoOwner == "" {
+			p.repoOwner = repoContext.Owner
+		}
+
+		if p.repoName == "" {
+			p.repoName = repoContext.Name
+		}
+
+		repoHost = repoContext.Host
 	}
 
 	data, err := os.ReadFile(filepath.Clean(p.path))
@@ -55,154 +135,330 @@ func (p *parser) Parse() (changelog.Changelog, error) {
 		return nil, err
 	}
 
-	markdownParser := mdparser.New()
-	output := markdownParser.Parse(data)
-
-	var tagIndex []string // This is a list of tags in order
-	var unreleased []string
-	var entries = map[string]*entry.Entry{} // Maintain a map of tag to entry
-	var currentTag string
-	var currentSection string
-
-	for _, child := range output.GetChildren() {
-		switch child.(type) {
-		case *ast.Heading:
-			if isHeading(child, 2) {
-				currentTag = getTagFromHeadingLink(child)
-				if currentTag == "" && isHeadingUnreleased(child) {
-					currentTag = "Unreleased"
-					continue
-				}
-				date := getDateFromHeading(child)
-				if _, ok := entries[currentTag]; !ok {
-					e := entry.NewEntry(currentTag, date)
-					entries[currentTag] = &e
-					tagIndex = append(tagIndex, currentTag)
-				}
-			}
+	s := &state{
+		path:       p.path,
+		collectAll: collectAll,
+		block:      blockPreamble,
+	}
+	inCodeBlock := false
 
-			if isHeading(child, 3) {
-				currentSection = getTextFromChildNodes(child)
-			}
-		case *ast.List:
-			items := getItemsFromList(child)
-			if currentTag == "Unreleased" {
-				for _, item := range items {
-					unreleased = append(unreleased, getTextFromChildNodes(item))
-				}
-				continue
-			}
+	for i, line := range strings.Split(string(data), "\n") {
+		s.line = i + 1
 
-			for _, item := range items {
-				err := entries[currentTag].Append(currentSection, getTextFromChildNodes(item))
-				if err != nil {
-					// TODO: Add more context to this error
-					return nil, fmt.Errorf("error parsing changelog: %s", err)
-				}
-			}
-		default:
-			// TODO: Add more context to this block
-			// We are ignoring other types of nodes for now
+		if isCodeFence(line) {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if inCodeBlock || isCodeFence(line) {
+			s.lines = append(s.lines, line)
 			continue
 		}
+
+		if err := s.parseLine(line); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.flush(); err != nil {
+		return nil, err
 	}
 
 	cl := changelog.NewChangelog(p.repoOwner, p.repoName)
+	cl.SetRepoHost(repoHost)
+	cl.SetPreamble(strings.Join(trimBlankLines(s.preamble, false), "\n"))
+
+	cl.SetUnreleasedDescription(s.unreleasedDescription)
 
-	if len(unreleased) > 0 {
-		cl.AddUnreleased(unreleased)
+	if len(s.unreleased) > 0 {
+		cl.AddUnreleased(s.unreleased)
 	}
 
-	for _, tag := range tagIndex {
-		cl.Insert(*entries[tag])
+	for _, e := range s.entries {
+		cl.Insert(*e)
+	}
+
+	if len(s.errors) > 0 {
+		return cl, s.errors
 	}
 
 	return cl, nil
 }
 
-func isListItem(node ast.Node) bool {
-	_, ok := node.(*ast.ListItem)
-	return ok
+// parseLine adds a line that is outside of a code block to the changelog.
+func (s *state) parseLine(line string) error {
+	switch {
+	case strings.HasPrefix(line, "## "):
+		return s.startEntry(line)
+	case strings.HasPrefix(line, "### ") && s.entry != nil:
+		return s.startSection(line)
+	case s.inList && isListItem(line):
+		if err := s.flush(); err != nil {
+			return err
+		}
+		s.block = blockItem
+		if s.section == contributorsHeading {
+			s.block = blockContributor
+		}
+		s.blockLine = s.line
+		s.lines = []string{line[2:]}
+	case s.block == blockEntry && s.isFullChangelogLink(line):
+		// The link is generated when the changelog is written, so only
+		// the ref that the entry is compared with needs to be kept. The URL
+		// is kept so that it can be checked.
+		m := fullChangelogRegex.FindStringSubmatch(strings.TrimSpace(line))
+		s.entry.CompareURL = m[1]
+		s.entry.PrevTag = m[2]
+	default:
+		s.lines = append(s.lines, line)
+	}
+
+	return nil
 }
 
-func isLink(node ast.Node) bool {
-	_, ok := node.(*ast.Link)
-	return ok
+// isFullChangelogLink returns true if the line is the link that compares the
+// current entry with the previous one. Links that do not end at the tag of
+// the entry are kept as text.
+func (s *state) isFullChangelogLink(line string) bool {
+	m := fullChangelogRegex.FindStringSubmatch(strings.TrimSpace(line))
+	return m != nil && m[3] == s.entry.Tag
 }
 
-func isText(node ast.Node) bool {
-	_, ok := node.(*ast.Text)
-	return ok
+// fail records a problem at the current line. An error is only returned if
+// parsing should stop.
+func (s *state) fail(err *ParseError) error {
+	err.Path = s.path
+	if err.Line == 0 {
+		err.Line = s.line
+	}
+
+	s.errors = append(s.errors, err)
+	if s.collectAll {
+		return nil
+	}
+
+	return err
 }
 
-func isHeading(node ast.Node, level int) bool {
-	_, heading := node.(*ast.Heading)
-	ok := heading && node.(*ast.Heading).Level == level
-	return ok
+// startEntry begins a new entry, or the Unreleased section, from a level two
+// heading. Headings that are not recognised are treated as text.
+func (s *state) startEntry(line string) error {
+	m := entryHeadingRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if m != nil && !isEntryHeading(m) {
+		m = nil
+	}
+
+	if m == nil && !strings.Contains(line, unreleasedHeading) {
+		s.lines = append(s.lines, line)
+		return nil
+	}
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	s.section = ""
+
+	var tag, date string
+	if m != nil {
+		tag = m[1] + m[2]
+		date = m[3]
+	}
+
+	if m == nil || strings.EqualFold(tag, unreleasedHeading) {
+		s.block = blockUnreleased
+		s.inList = true
+		s.entry = nil
+		return nil
+	}
+
+	e := entry.NewEntry(tag, time.Time{})
+	e.Line = s.line
+	s.entries = append(s.entries, &e)
+	s.entry = &e
+	s.block = blockEntry
+	s.inList = false
+
+	if date != "" {
+		parsed, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+		if err != nil {
+			return s.fail(&ParseError{
+				Column:  strings.LastIndex(line, date) + 1,
+				Text:    date,
+				Message: fmt.Sprintf("invalid date for %s", tag),
+				Hint:    "dates should be in the format YYYY-MM-DD",
+			})
+		}
+		e.Date = parsed
+	}
+
+	return nil
 }
 
-func isParagraph(node ast.Node) bool {
-	_, ok := node.(*ast.Paragraph)
-	return ok
+// isEntryHeading returns true if a heading that matched entryHeadingRegex is
+// the heading of an entry. A heading without a link, such as ## Notes, is
+// only an entry when it is a version or is followed by a date.
+func isEntryHeading(m []string) bool {
+	if m[1] != "" || m[3] != "" || strings.EqualFold(m[2], unreleasedHeading) {
+		return true
+	}
+
+	_, err := version.NormalizeVersion(m[2])
+	return err == nil
 }
 
-func getItemsFromList(node ast.Node) []*ast.ListItem {
-	var items []*ast.ListItem
-	for _, child := range node.GetChildren() {
-		if isListItem(child) {
-			items = append(items, child.(*ast.ListItem))
+// startSection begins a new section in the current entry. Sections are
+// created as soon as they are found so that empty sections are kept.
+func (s *state) startSection(line string) error {
+	name := strings.TrimSpace(strings.TrimPrefix(line, "### "))
+	if name == "" {
+		// The heading is kept as text so that parsing can continue.
+		s.lines = append(s.lines, line)
+		return s.fail(&ParseError{
+			Column:  1,
+			Message: "section heading is empty",
+			Hint:    "sections should have a name, for example ### Added",
+		})
+	}
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	s.inList = true
+
+	// The contributors of an entry are not a section of changes.
+	if strings.EqualFold(name, contributorsHeading) {
+		s.block = blockContributors
+		s.blockLine = s.line
+		s.section = contributorsHeading
+		return nil
+	}
+
+	s.block = blockSection
+	s.section = name
+
+	for _, section := range s.entry.Sections {
+		if strings.EqualFold(section.Name, name) {
+			return nil
 		}
 	}
-	return items
+
+	s.entry.Sections = append(s.entry.Sections, entry.Section{Name: name, Line: s.line})
+
+	return nil
 }
 
-func getTextFromChildNodes(node ast.Node) string {
-	var text []string
-	for _, child := range node.GetChildren() {
-		if isParagraph(child) {
-			text = append(text, getTextFromChildNodes(child)) // This stinks
-			// text = append(text, "\n")                         // so does this
-		}
+// flush moves the lines that have been collected in to the part of the
+// changelog that they belong to.
+func (s *state) flush() error {
+	lines := s.lines
+	s.lines = nil
 
-		if isText(child) {
-			text = append(text, string(child.(*ast.Text).Literal))
+	switch s.block {
+	case blockPreamble:
+		s.preamble = append(s.preamble, lines...)
+	case blockUnreleased:
+		s.unreleasedDescription = strings.Join(trimBlankLines(lines, true), "\n")
+	case blockEntry:
+		s.entry.Description = strings.Join(trimBlankLines(lines, true), "\n")
+	case blockSection:
+		for i := range s.entry.Sections {
+			if strings.EqualFold(s.entry.Sections[i].Name, s.section) {
+				s.entry.Sections[i].Description = strings.Join(trimBlankLines(lines, true), "\n")
+			}
+		}
+	case blockItem:
+		item := newItem(strings.Join(trimBlankLines(lines, false), "\n"))
+		if s.entry == nil {
+			s.unreleased = append(s.unreleased, item)
+			return nil
 		}
 
-		if isLink(child) {
-			linkText := getTextFromChildNodes(child)
-			link := fmt.Sprintf("[%s](%s)", linkText, child.(*ast.Link).Destination)
-			text = append(text, link)
+		if err := s.entry.Append(s.section, item); err != nil {
+			return s.fail(&ParseError{
+				Line:    s.blockLine,
+				Column:  1,
+				Text:    item.Text,
+				Message: err.Error(),
+			})
+		}
+	case blockContributors:
+		if text := trimBlankLines(lines, true); len(text) > 0 {
+			return s.failContributor(strings.Join(text, "\n"))
+		}
+	case blockContributor:
+		text := strings.Join(trimBlankLines(lines, false), "\n")
+		m := contributorRegex.FindStringSubmatch(text)
+		if m == nil {
+			return s.failContributor(text)
 		}
+
+		s.entry.Contributors = append(s.entry.Contributors, entry.Contributor{
+			Login:     m[1] + m[2],
+			FirstTime: m[3] != "",
+		})
 	}
-	return strings.Join(text, "")
+
+	return nil
 }
 
-func getTagFromHeadingLink(node ast.Node) string {
-	for _, child := range node.GetChildren() {
-		if isLink(child) {
-			return getTextFromChildNodes(child)
-		}
-	}
-	return ""
+// failContributor records text in the Contributors section of an entry that
+// is not a contributor.
+func (s *state) failContributor(text string) error {
+	return s.fail(&ParseError{
+		Line:    s.blockLine,
+		Column:  1,
+		Text:    text,
+		Message: "the Contributors section can only list contributors",
+		Hint:    "contributors should be in the format - [@user](https://github.com/user)",
+	})
 }
 
-func getDateFromHeading(node ast.Node) time.Time {
-	var date time.Time
-	for _, child := range node.GetChildren() {
-		if isText(child) {
-			text := string(child.(*ast.Text).Literal)
-			if text != "" {
-				date, err := time.Parse("2006-01-02", strings.ReplaceAll(text, " - ", ""))
-				if err != nil {
-					panic(err)
-				}
-				return date
-			}
-		}
+// newItem creates an item from its text in the changelog. The original text is
+// always kept. If the first line is in the format produced by this tool, the
+// pull request details are also extracted.
+func newItem(text string) entry.Item {
+	item := entry.Item{
+		Text: text,
 	}
-	return date
+
+	m := itemRegex.FindStringSubmatch(strings.SplitN(text, "\n", 2)[0])
+	if m == nil {
+		return item
+	}
+
+	number, err := strconv.Atoi(m[2])
+	if err != nil {
+		return item
+	}
+
+	item.Title = m[1]
+	item.Number = number
+	item.URL = m[3]
+	item.Author = m[4]
+
+	return item
+}
+
+func isListItem(line string) bool {
+	return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")
 }
 
-func isHeadingUnreleased(node ast.Node) bool {
-	return strings.Contains(getTextFromChildNodes(node), "Unreleased")
+func isCodeFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// trimBlankLines removes blank lines from the end of lines and, if leading is
+// true, from the start.
+func trimBlankLines(lines []string, leading bool) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for leading && len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	return lines
 }
@@ -1,8 +1,13 @@
 package parser_test
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/chelnak/gh-changelog/internal/writer"
+	"github.com/chelnak/gh-changelog/pkg/entry"
 	"github.com/chelnak/gh-changelog/pkg/parser"
 	"github.com/stretchr/testify/require"
 )
@@ -23,4 +28,190 @@ func TestParser(t *testing.T) {
 		require.Len(t, c.GetUnreleased(), 0)
 		require.Len(t, c.GetEntries(), 3)
 	})
+
+	t.Run("does not need a local repository when the owner and name are given", func(t *testing.T) {
+		path, err := filepath.Abs("./testdata/no_unreleased.md")
+		require.NoError(t, err)
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		defer func() { _ = os.Chdir(wd) }()
+
+		t.Setenv("GH_REPO", "")
+		t.Setenv("GH_HOST", "ghes.example.com")
+
+		p := parser.NewParser(path, "chelnak", "gh-changelog")
+		c, err := p.Parse()
+		require.NoError(t, err)
+		require.Equal(t, "chelnak", c.GetRepoOwner())
+		require.Equal(t, "ghes.example.com", c.GetRepoHost())
+	})
+
+	t.Run("reads the details of each item", func(t *testing.T) {
+		p := parser.NewParser("./testdata/no_unreleased.md", "chelnak", "gh-changelog")
+		c, err := p.Parse()
+		require.NoError(t, err)
+
+		items := c.GetEntries()[0].GetSection("fixed")
+		require.Len(t, items, 1)
+		require.Equal(t, "bugfix: Release creation toggling RepoName & RepoOwner", items[0].Title)
+		require.Equal(t, 142, items[0].Number)
+		require.Equal(t, "https://github.com/chelnak/gh-changelog/pull/142", items[0].URL)
+		require.Equal(t, "Ramesh7", items[0].Author)
+	})
+
+	t.Run("keeps content that is not part of an item", func(t *testing.T) {
+		p := parser.NewParser("./testdata/hand_edited.md", "chelnak", "gh-changelog")
+		c, err := p.Parse()
+		require.NoError(t, err)
+
+		require.Contains(t, c.GetPreamble(), "# Changelog")
+		require.Contains(t, c.GetPreamble(), "Releases before v0.1.0 are not listed here.")
+		require.NotContains(t, c.GetPreamble(), "next release")
+		require.Equal(t, "These changes will be in the next release.", c.GetUnreleasedDescription())
+		require.Len(t, c.GetUnreleased(), 2)
+
+		entries := c.GetEntries()
+		require.Len(t, entries, 3)
+		require.Equal(t, "This release fixes a regression in v0.15.0.\n\nUpgrading is recommended.", entries[0].Description)
+		require.Equal(t, "v0.15.0", entries[0].PrevTag)
+
+		require.Equal(t, "Thanks to everyone who helped with this release.", entries[1].Sections[0].Description)
+
+		items := entries[1].GetSection("added")
+		require.Len(t, items, 1)
+		require.Equal(t, "Improve sections ordering", items[0].Title)
+		require.Contains(t, items[0].Text, "\n  - Sections are now ordered by the `section_order` configuration")
+		require.Contains(t, items[0].Text, "    ## not a heading")
+	})
+
+	t.Run("returns an error for an invalid date", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+		content := "# Changelog\n\n## [v1.0.0](https://github.com/chelnak/gh-changelog/tree/v1.0.0) - 15/04/2022\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+		p := parser.NewParser(path, "chelnak", "gh-changelog")
+		_, err := p.Parse()
+
+		var parseErr *parser.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, path, parseErr.Path)
+		require.Equal(t, 3, parseErr.Line)
+		require.Equal(t, 68, parseErr.Column)
+		require.Equal(t, "15/04/2022", parseErr.Text)
+		require.Equal(t, "dates should be in the format YYYY-MM-DD", parseErr.Hint)
+		require.Equal(t, path+`:3:68: invalid date for v1.0.0 "15/04/2022" (dates should be in the format YYYY-MM-DD)`, err.Error())
+	})
+
+	t.Run("can collect all problems", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+		content := `# Changelog
+
+## [v1.1.0](https://github.com/chelnak/gh-changelog/tree/v1.1.0) - 2022-04-31
+
+### 
+
+- An item without a section
+
+## [v1.0.0](https://github.com/chelnak/gh-changelog/tree/v1.0.0) - 2022-04-15
+
+### Added
+
+- An item
+`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+		p := parser.NewParser(path, "chelnak", "gh-changelog")
+		_, err := p.Parse()
+
+		var parseErr *parser.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 3, parseErr.Line)
+
+		c, err := p.ParseAll()
+
+		var parseErrs parser.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 2)
+		require.Equal(t, 3, parseErrs[0].Line)
+		require.Equal(t, 5, parseErrs[1].Line)
+		require.Equal(t, "section heading is empty", parseErrs[1].Message)
+
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 3, parseErr.Line)
+
+		require.Len(t, c.GetEntries(), 2)
+		require.Equal(t, "An item", c.GetEntries()[1].GetSection("added")[0].Text)
+	})
+}
+
+func TestParserContributors(t *testing.T) {
+	t.Run("reads the contributors of each entry", func(t *testing.T) {
+		p := parser.NewParser("./testdata/contributors.md", "chelnak", "gh-changelog")
+		c, err := p.Parse()
+		require.NoError(t, err)
+
+		entries := c.GetEntries()
+		require.Equal(t, []entry.Contributor{{Login: "Ramesh7", FirstTime: true}}, entries[0].Contributors)
+		require.Equal(t, []entry.Contributor{{Login: "chelnak"}, {Login: "smortex"}}, entries[1].Contributors)
+		require.Nil(t, entries[0].GetSection("contributors"))
+	})
+
+	t.Run("reports text in the contributors section", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+		data := "## [v1.0.0](https://github.com/chelnak/gh-changelog/tree/v1.0.0) - 2023-10-09\n\n### Contributors\n\n- Thanks to everyone!\n"
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+		p := parser.NewParser(path, "chelnak", "gh-changelog")
+		_, err := p.Parse()
+
+		var parseErr *parser.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 5, parseErr.Line)
+		require.Equal(t, "the Contributors section can only list contributors", parseErr.Message)
+	})
+}
+
+func TestParserHeadings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	data := "## Notes\n\nSome notes.\n\n## v1.1.0\n\n## nightly - 2023-10-09\n\n## Migration\n\n## 1.0.0 - 2023-10-01\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	p := parser.NewParser(path, "chelnak", "gh-changelog")
+	c, err := p.Parse()
+	require.NoError(t, err)
+
+	var tags []string
+	for _, e := range c.GetEntries() {
+		tags = append(tags, e.Tag)
+	}
+
+	// Headings without a link are only entries when they are a version or have a date.
+	require.Equal(t, []string{"v1.1.0", "nightly", "1.0.0"}, tags)
+}
+
+func TestParserRoundTrip(t *testing.T) {
+	files := []string{
+		"./testdata/unreleased.md",
+		"./testdata/no_unreleased.md",
+		"./testdata/hand_edited.md",
+		"./testdata/contributors.md",
+		"./testdata/unknown_headings.md",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			original, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			p := parser.NewParser(file, "chelnak", "gh-changelog")
+			c, err := p.Parse()
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, writer.Write(&buf, writer.TmplSrcStandard, c))
+			require.Equal(t, string(original), buf.String())
+		})
+	}
 }